@@ -0,0 +1,45 @@
+package blush
+
+import (
+	"bufio"
+
+	"github.com/arsham/blush/internal/tools"
+)
+
+// BinaryMode decides how the sources that are not plain text are read.
+type BinaryMode int
+
+const (
+	// BinaryMatch reads binary sources, but instead of their lines it prints
+	// the BinaryMatches notice if any of their lines are selected. This is the
+	// default mode, therefore binary files are never written to the output
+	// unless it is asked for.
+	BinaryMatch BinaryMode = iota
+	// BinarySkip doesn't read binary sources.
+	BinarySkip
+	// BinaryText reads binary sources as if they were plain texts.
+	BinaryText
+)
+
+// BinaryMatches is printed with the name of a binary source that has selected
+// lines in the BinaryMatch mode.
+const BinaryMatches = "Binary file %s matches\n"
+
+// binarySample is the number of bytes at the beginning of a source that is
+// used to decide whether it is binary.
+const binarySample = 512
+
+// isBinary reports whether the beginning of the input is not plain text. It
+// doesn't consume the input.
+func isBinary(r *bufio.Reader) bool {
+	return !tools.IsPlainText(string(peek(r, binarySample)))
+}
+
+// peek returns the next n bytes of r without reading them, or less if the
+// input is shorter. Peek returns an error with the bytes that it could get in
+// that case, which are still valid, therefore the error is dropped. The error
+// of reading r is returned by its next read.
+func peek(r *bufio.Reader, n int) []byte {
+	b, _ := r.Peek(n) // nolint:errcheck // explained above.
+	return b
+}
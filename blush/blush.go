@@ -2,17 +2,34 @@ package blush
 
 import (
 	"bufio"
+	"errors"
+	"fmt"
 	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/arsham/blush/internal/reader"
 )
 
 type mode int
 
+// ContextStyle is the style of the context lines.
+var ContextStyle = Style{Colour: NoColour, Attributes: Dim}
+
 const (
 	// Separator string between name of the reader and the contents.
 	Separator = ": "
 
+	// GroupSeparator is written between groups of lines that are not next to
+	// each other when context lines are printed.
+	GroupSeparator = "--\n"
+
+	// UnnamedSource is written instead of the name of a source that has no
+	// name when the files are listed.
+	UnnamedSource = "(standard input)"
+
 	// DefaultLineCache is minimum lines to cache.
 	DefaultLineCache = 50
 
@@ -24,23 +41,110 @@ const (
 	writeToMode
 )
 
+// sourcer is implemented by readers that read from several sources, like
+// files, one after another. Blush reads each source separately to keep track of
+// the line numbers and the byte offsets in each of them. CloseSource is called
+// when the rest of the current source is not read.
+type sourcer interface {
+	NextSource() (name string, r io.Reader, err error)
+	CloseSource() error
+}
+
+// follower is implemented by sourcers that keep returning the contents that
+// are appended to their sources, as new sources with the same names. The line
+// numbers and the byte offsets of those sources continue from the previous
+// ones with the same name, and MaxCount limits all of them together.
+type follower interface {
+	Following() bool
+}
+
+// offsetter is implemented by the sources that start after the beginning of
+// their inputs, like the last lines of a file. The byte offsets of their lines
+// start from the Offset, which is known after they are read for the first
+// time.
+type offsetter interface {
+	Offset() int64
+}
+
+// followed is the state of a followed source that is kept for the contents
+// that are appended to it.
+type followed struct {
+	pos     position
+	matched int // number of the selected lines.
+}
+
+// position is the location of a line in its source.
+type position struct {
+	name   string
+	line   int // starts from 1.
+	offset int // the byte offset of the start of the line.
+}
+
 // Blush reads from reader and matches against all finders. If NoCut is true,
-// any unmatched lines are printed as well. If WithFileName is true, blush will
-// write the filename before it writes the output. Read and WriteTo will return
-// ErrReadWriteMix if both Read and WriteTo are called on the same object. See
-// package docs for more details.
+// any unmatched lines are printed as well. If Invert is true, only the lines
+// that none of the finders match are printed. Any lines that match one of the
+// Exclude finders are never printed. ContextBefore and ContextAfter are the
+// number of lines that are printed before and after each printed line, even if
+// they would be dropped otherwise. They are painted with the ContextStyle. If
+// WithFileName is true, blush will write the filename before it writes the
+// output. If LineNumber or ByteOffset are true, the line number or the byte
+// offset of the start of the line in its source is written after the file name.
+// When the Reader reads from several files, the line numbers and the offsets
+// start from the beginning in each file. If the Reader follows its files, they
+// continue in the contents that are appended to the files. If OnlyMatching is
+// true, only the matches of the finders that implement the Indexer interface
+// are printed, each on its own line. If Count is true, the number of the lines
+// that would be printed with Drop is printed for each file instead of the
+// lines. If FilesWithMatches or FilesWithoutMatch is true, only the names of
+// the sources that have or don't have any selected lines are printed, and the
+// rest of each source is skipped as soon as it has a selected line. MaxCount
+// and MaxTotal limit the number of the selected lines of each source and of all
+// of them; the reading of a source stops when its limit is reached, and the
+// reading of the Reader stops when MaxTotal is reached. They are not limited if
+// they are zero. BinaryFiles decides how the sources that are not plain text
+// are read. If Quiet is true, nothing is printed and the reading stops at the
+// first line that is selected. Stats returns the statistics of the input after
+// it is read, and its Matched field tells if any lines are selected. The
+// Renderer decorates the matches, and if it is nil the DefaultRenderer is used.
+// Read and WriteTo will return ErrReadWriteMix if both Read and WriteTo are
+// called on the same object. See package docs for more details.
 // nolint:govet // we are expecting lots of these objects.
 type Blush struct {
-	Finders      []Finder
-	Reader       io.ReadCloser
-	LineCache    uint
-	CharCache    uint
+	Finders   []Finder
+	Reader    io.ReadCloser
+	Renderer  Renderer
+	LineCache uint
+	CharCache uint
+	// ContextBefore is the number of lines to print before each matched line.
+	ContextBefore uint
+	// ContextAfter is the number of lines to print after each matched line.
+	ContextAfter uint
+	Exclude      []Finder
 	Drop         bool // do not cut out non-matched lines.
+	Invert       bool
 	WithFileName bool
-	closed       bool
-	readLineCh   chan []byte
-	readCh       chan byte
-	mode         mode
+	LineNumber   bool
+	ByteOffset   bool
+	OnlyMatching bool
+	Count        bool
+	Quiet        bool
+	// FilesWithMatches prints the names of the sources with selected lines.
+	FilesWithMatches bool
+	// FilesWithoutMatch prints the names of the sources without any selected
+	// lines.
+	FilesWithoutMatch bool
+	// MaxCount is the maximum number of selected lines of each source.
+	MaxCount uint
+	// MaxTotal is the maximum number of selected lines of all sources.
+	MaxTotal uint
+	// BinaryFiles decides how the binary sources are read.
+	BinaryFiles BinaryMode
+	closed      bool
+	readLineCh  chan []byte
+	readCh      chan byte
+	mode        mode
+	stats       Stats
+	err         error // the error of reading from the Reader.
 }
 
 // Read creates a goroutine on first invocation to read from the underlying
@@ -63,6 +167,9 @@ func (b *Blush) Read(p []byte) (n int, err error) {
 	for n = 0; n < cap(p); n++ {
 		c, ok := <-b.readCh
 		if !ok {
+			if b.err != nil {
+				return n, b.err
+			}
 			return n, io.EOF
 		}
 		p[n] = c
@@ -71,7 +178,9 @@ func (b *Blush) Read(p []byte) (n int, err error) {
 }
 
 // WriteTo writes matches to w. It returns an error if the writer is nil or
-// there are not paths defined or there is no files found in the Reader.
+// there are not paths defined or there is no files found in the Reader. It also
+// returns the error of reading from the Reader after the lines that are read
+// before the error are written.
 func (b *Blush) WriteTo(w io.Writer) (int64, error) {
 	if b.closed {
 		return 0, ErrClosed
@@ -94,14 +203,14 @@ func (b *Blush) WriteTo(w io.Writer) (int64, error) {
 		}
 		total += len(line)
 	}
-	return int64(total), nil
+	return int64(total), b.err
 }
 
 func (b *Blush) setup(m mode) error {
 	if b.Reader == nil {
 		return reader.ErrNoReader
 	}
-	if len(b.Finders) < 1 {
+	if len(b.Finders) < 1 && len(b.Exclude) < 1 {
 		return ErrNoFinder
 	}
 
@@ -112,6 +221,10 @@ func (b *Blush) setup(m mode) error {
 	if b.CharCache == 0 {
 		b.CharCache = DefaultCharCache
 	}
+	b.stats = Stats{Finders: make([]FinderStats, len(b.Finders))}
+	for i, f := range b.Finders {
+		b.stats.Finders[i].Finder = f
+	}
 	b.readLineCh = make(chan []byte, b.LineCache)
 	b.readCh = make(chan byte, b.CharCache)
 	go b.readLines()
@@ -121,33 +234,325 @@ func (b *Blush) setup(m mode) error {
 	return nil
 }
 
-func (b *Blush) decorate(input string) (string, bool) {
-	str, ok := lookInto(b.Finders, input)
-	if ok || !b.Drop {
-		var prefix string
-		if b.WithFileName {
-			prefix = fileName(b.Reader)
+// decorate returns the decorated line, or false if the line should not be
+// printed. A line is selected if the finders match it, or if they don't when
+// Invert is set, and the number of selected lines is recorded in the stats.
+// When there are only Exclude finders, Drop has no effect and all the lines
+// that are not excluded are selected.
+func (b *Blush) decorate(input string, pos position) (string, bool) {
+	if matchesAny(b.Exclude, input) {
+		return "", false
+	}
+	r := b.renderer()
+	if b.OnlyMatching {
+		if b.Invert {
+			return "", false
+		}
+		str, ok := b.onlyMatching(input, pos, r)
+		if ok {
+			b.stats.Matched++
+		}
+		return str, ok
+	}
+	str, ok := lookInto(b.Finders, input, r, b.found)
+	selected := ok != b.Invert || len(b.Finders) == 0
+	if selected {
+		b.stats.Matched++
+	} else if b.Invert || b.Drop || b.Count {
+		return "", false
+	}
+	return b.prefix(r, pos) + str, true
+}
+
+// onlyMatching returns each match of the Indexers in the line on its own line,
+// painted with the style of its finder. When matches overlap, only the one that
+// starts first is returned. The byte offset in the prefix is the offset of the
+// match.
+func (b *Blush) onlyMatching(line string, pos position, r Renderer) (string, bool) {
+	var matches []Match
+	for i, f := range b.Finders {
+		if ix, ok := f.(Indexer); ok {
+			if m := ix.FindIndex(line); len(m) > 0 {
+				matches = append(matches, m...)
+				b.found(i, len(m))
+			}
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Start < matches[j].Start
+	})
+	var (
+		sb  strings.Builder
+		end int
+	)
+	for _, m := range matches {
+		text := strings.TrimRight(line[m.Start:m.End], "\r\n")
+		if m.Start < end || text == "" {
+			continue
 		}
-		return prefix + str, true
+		p := pos
+		p.offset += m.Start
+		sb.WriteString(b.prefix(r, p))
+		sb.WriteString(r.Render(text, []Match{{Finder: m.Finder, Style: m.Style, End: len(text)}}))
+		sb.WriteString("\n")
+		end = m.End
+	}
+	if sb.Len() == 0 {
+		return "", false
+	}
+	return sb.String(), true
+}
+
+// count returns the number of the lines of a source, which is prefixed with
+// its name if WithFileName is set.
+func (b *Blush) count(name string, n int) string {
+	var prefix string
+	if b.WithFileName && name != "" {
+		prefix = b.renderer().Render(name+Separator, nil)
+	}
+	return prefix + strconv.Itoa(n) + "\n"
+}
+
+// reachedMax reports whether the number of the selected lines has reached
+// MaxCount in the current source, which had matched selected lines before it,
+// or MaxTotal in all sources.
+func (b *Blush) reachedMax(matched int) bool {
+	if b.MaxTotal > 0 && b.stats.Matched >= int(b.MaxTotal) {
+		return true
+	}
+	return b.MaxCount > 0 && b.stats.Matched-matched >= int(b.MaxCount)
+}
+
+// binaryMatches returns the BinaryMatches notice of a source.
+func (b *Blush) binaryMatches(name string) string {
+	if name == "" {
+		name = UnnamedSource
+	}
+	return b.renderer().Render(fmt.Sprintf(BinaryMatches, name), nil)
+}
+
+// listName returns the name of a source in its own line for listing the files.
+func (b *Blush) listName(name string) string {
+	if name == "" {
+		name = UnnamedSource
 	}
-	return "", false
+	return b.renderer().Render(name, nil) + "\n"
 }
 
+// context returns the line painted with the ContextStyle.
+func (b *Blush) context(line string, pos position) string {
+	r := b.renderer()
+	text := strings.TrimRight(line, "\r\n")
+	m := []Match{{Style: ContextStyle, Start: 0, End: len(text)}}
+	return b.prefix(r, pos) + r.Render(line, m)
+}
+
+// prefix returns the file name, the line number and the byte offset of the
+// line, depending on which ones are set, for example "main.go:12:345: ".
+func (b *Blush) prefix(r Renderer, pos position) string {
+	var parts []string
+	if b.WithFileName && pos.name != "" {
+		parts = append(parts, pos.name)
+	}
+	if b.LineNumber {
+		parts = append(parts, strconv.Itoa(pos.line))
+	}
+	if b.ByteOffset {
+		parts = append(parts, strconv.Itoa(pos.offset))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return r.Render(strings.Join(parts, ":")+Separator, nil)
+}
+
+func (b *Blush) renderer() Renderer {
+	if b.Renderer == nil {
+		return DefaultRenderer
+	}
+	return b.Renderer
+}
+
+// readLines decorates the lines and sends the ones that should be printed to
+// the readLineCh. If the Reader is a sourcer, each source is read separately.
+// The dropped lines are kept for the ContextBefore lines of the next printed
+// line, and the GroupSeparator is sent when some lines are dropped between two
+// groups of printed lines.
 func (b *Blush) readLines() {
+	start := time.Now()
+	defer func() {
+		b.stats.Elapsed = time.Since(start)
+		close(b.readLineCh)
+	}()
 	var (
-		ok bool
-		sc = bufio.NewReader(b.Reader)
+		printed bool // true if any lines are printed.
+		gap     bool // true if any lines are dropped since the last printed line.
+
+		ctxBefore, ctxAfter = b.ContextBefore, b.ContextAfter
 	)
+	list := b.FilesWithMatches || b.FilesWithoutMatch
+	if b.OnlyMatching || b.Count || list {
+		// the matches, the counts and the names are not lines, therefore they
+		// don't have any context.
+		ctxBefore, ctxAfter = 0, 0
+	}
+	context := ctxBefore > 0 || ctxAfter > 0
+	f, follow := b.Reader.(follower)
+	follow = follow && f.Following()
+	states := make(map[string]followed) // states of the followed sources.
+	// read returns false if the reading should stop.
+	read := func(name string, r io.Reader, sources bool) bool {
+		var (
+			before  []string   // dropped lines that can be the context of the next line.
+			bpos    []position // positions of the before lines.
+			after   uint       // number of lines that are left from the after context.
+			count   int        // number of the matched lines in the Count mode.
+			done    bool       // true if the rest of the source is not needed.
+			limited bool       // true if MaxCount or MaxTotal is reached.
+			pos     = position{name: name}
+			sc      = bufio.NewReader(r)
+
+			matched = b.stats.Matched
+			binary  = b.BinaryFiles != BinaryText && isBinary(sc)
+		)
+		if binary && b.BinaryFiles == BinarySkip {
+			return true
+		}
+		if fs, ok := states[name]; ok {
+			pos, matched = fs.pos, matched-fs.matched
+			if b.reachedMax(matched) {
+				// the limit of the source is reached with its previous contents.
+				return true
+			}
+		} else {
+			b.stats.Files++
+			if o, ok := r.(offsetter); ok {
+				// the offset is known after the source is read.
+				peek(sc, 1)
+				pos.offset = int(o.Offset())
+			}
+		}
+		if follow {
+			defer func() { states[name] = followed{pos: pos, matched: b.stats.Matched - matched} }()
+		}
+		for {
+			line, err := sc.ReadString('\n')
+			if err != nil && !errors.Is(err, io.EOF) && b.err == nil {
+				b.err = err
+			}
+			if line == "" {
+				// there is nothing after the last newline.
+				break
+			}
+			b.stats.Lines++
+			b.stats.Bytes += int64(len(line))
+			if sources && !strings.HasSuffix(line, "\n") {
+				// the next source should start from a new line.
+				line += "\n"
+			}
+			pos.line++
+			if !sources {
+				pos.name = strings.TrimSuffix(fileName(b.Reader), Separator)
+			}
+			if limited {
+				// only the after context of the last selected line is left.
+				b.readLineCh <- []byte(b.context(line, pos))
+				after--
+				if after == 0 || err != nil {
+					break
+				}
+				pos.offset += len(line)
+				continue
+			}
+			s, ok := b.decorate(line, pos)
+			switch {
+			case b.Quiet && !b.FilesWithoutMatch:
+				if b.stats.Matched > 0 {
+					return false
+				}
+			case list:
+				done = b.stats.Matched > matched
+			case b.Count:
+				if ok {
+					count++
+				}
+			case binary:
+				if b.stats.Matched > matched {
+					b.readLineCh <- []byte(b.binaryMatches(pos.name))
+					done = true
+				}
+			case ok:
+				if context && printed && gap {
+					b.readLineCh <- []byte(GroupSeparator)
+				}
+				for i, c := range before {
+					b.readLineCh <- []byte(b.context(c, bpos[i]))
+				}
+				b.readLineCh <- []byte(s)
+				before, bpos = before[:0], bpos[:0]
+				after = ctxAfter
+				printed, gap = true, false
+			case after > 0:
+				b.readLineCh <- []byte(b.context(line, pos))
+				after--
+			case ctxBefore > 0:
+				if uint(len(before)) == ctxBefore {
+					before, bpos = before[1:], bpos[1:]
+					gap = true
+				}
+				before, bpos = append(before, line), append(bpos, pos)
+			default:
+				gap = true
+			}
+			pos.offset += len(line)
+			limited = b.reachedMax(matched)
+			if err != nil || done || (limited && after == 0) {
+				break
+			}
+		}
+		if len(before) > 0 {
+			gap = true
+		}
+		if list {
+			if b.FilesWithMatches == (b.stats.Matched > matched) {
+				b.stats.Listed++
+				if !b.Quiet {
+					b.readLineCh <- []byte(b.listName(pos.name))
+				}
+			}
+			return true
+		}
+		if b.Count && !b.Quiet {
+			b.readLineCh <- []byte(b.count(pos.name, count))
+		}
+		return b.MaxTotal == 0 || b.stats.Matched < int(b.MaxTotal)
+	}
+
+	s, ok := b.Reader.(sourcer)
+	if !ok {
+		read("", b.Reader, false)
+		return
+	}
 	for {
-		line, err := sc.ReadString('\n')
-		if line, ok = b.decorate(line); ok {
-			b.readLineCh <- []byte(line)
+		name, r, err := s.NextSource()
+		if errors.Is(err, io.EOF) {
+			return
 		}
 		if err != nil {
-			break
+			// the rest of the sources are still read, and the first error is
+			// returned when they are done.
+			if b.err == nil {
+				b.err = err
+			}
+			continue
+		}
+		if !read(name, r, true) {
+			if err := s.CloseSource(); err != nil && b.err == nil {
+				b.err = err
+			}
+			return
 		}
 	}
-	close(b.readLineCh)
 }
 
 func (b *Blush) transfer() {
@@ -166,16 +571,54 @@ func (b *Blush) Close() error {
 }
 
 // lookInto returns a new decorated line if any of the finders decorate it, or
-// the given line as it is.
-func lookInto(f []Finder, line string) (string, bool) {
-	var found bool
-	for _, a := range f {
-		if s, ok := a.Find(line); ok {
+// the given line as it is. The matches of all finders are located in the
+// original line and are painted by r in one go. Finders that cannot report
+// their match locations are applied on the result afterwards. The found
+// function is called with the index of each finder that matches the line and
+// the number of its matches.
+func lookInto(f []Finder, line string, r Renderer, found func(i, n int)) (string, bool) {
+	var (
+		matched bool
+		matches []Match
+		others  []int // indices of the finders that are not Indexers.
+	)
+	for i, a := range f {
+		ix, ok := a.(Indexer)
+		if !ok {
+			others = append(others, i)
+			continue
+		}
+		if m := ix.FindIndex(line); len(m) > 0 {
+			matches = append(matches, m...)
+			matched = true
+			found(i, len(m))
+		}
+	}
+	line = r.Render(line, matches)
+	for _, i := range others {
+		if s, ok := f[i].Find(line); ok {
 			line = s
-			found = true
+			matched = true
+			found(i, 1)
+		}
+	}
+	return line, matched
+}
+
+// matchesAny reports whether any of the finders match the line.
+func matchesAny(f []Finder, line string) bool {
+	for _, a := range f {
+		if ix, ok := a.(Indexer); ok {
+			if len(ix.FindIndex(line)) > 0 {
+				return true
+			}
+			continue
+		}
+		if _, ok := a.Find(line); ok {
+			return true
 		}
 	}
-	return line, found
+	return false
 }
 
 // fileName returns an empty string if it could not query the fileName from r.
@@ -138,3 +138,14 @@ func ExampleBlush_WriteTo_multiReaderInDetails() {
 	// n == len(expected): true
 	// buf.String() == expected: true
 }
+
+func ExampleExact_FindIndex() {
+	f := blush.NewExact("lie", blush.Red)
+	input := "He who lives in sin, will surely live the lie"
+	for _, m := range f.FindIndex(input) {
+		fmt.Printf("%d-%d: %s\n", m.Start, m.End, input[m.Start:m.End])
+	}
+
+	// Output:
+	// 42-45: lie
+}
@@ -8,6 +8,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -41,6 +42,24 @@ func testBlushWriteTo(t *testing.T) {
 	t.Run("Colour", testBlushWriteToColour)
 	t.Run("ColourNoCutMode", testBlushWriteToColourNoCutMode)
 	t.Run("MultipleMatchInOneLine", testBlushWriteToMultipleMatchInOneLine)
+	t.Run("EscapeSequences", testBlushWriteToEscapeSequences)
+	t.Run("OverlappingMatches", testBlushWriteToOverlappingMatches)
+	t.Run("Invert", testBlushWriteToInvert)
+	t.Run("Exclude", testBlushWriteToExclude)
+	t.Run("Context", testBlushWriteToContext)
+	t.Run("ContextStyle", testBlushWriteToContextStyle)
+	t.Run("Positions", testBlushWriteToPositions)
+	t.Run("OnlyMatching", testBlushWriteToOnlyMatching)
+	t.Run("Count", testBlushWriteToCount)
+	t.Run("Stats", testBlushWriteToStats)
+	t.Run("Matched", testBlushWriteToMatched)
+	t.Run("Quiet", testBlushWriteToQuiet)
+	t.Run("ReadError", testBlushWriteToReadError)
+	t.Run("Files", testBlushWriteToFiles)
+	t.Run("MaxCount", testBlushWriteToMaxCount)
+	t.Run("BinaryFiles", testBlushWriteToBinaryFiles)
+	t.Run("Follow", testBlushWriteToFollow)
+	t.Run("FollowMaxCount", testBlushWriteToFollowMaxCount)
 }
 
 func testBlushWriteToErrors(t *testing.T) {
@@ -294,6 +313,780 @@ func testBlushWriteToMultipleMatchInOneLine(t *testing.T) {
 	assert.EqualValues(t, match, example)
 }
 
+// Finders should never match the escape sequences of other finders.
+func testBlushWriteToEscapeSequences(t *testing.T) {
+	t.Parallel()
+	input := "an ERROR has occurred\n"
+	tcs := []struct {
+		name    string
+		finders []blush.Finder
+		want    string
+	}{
+		{
+			"digits",
+			[]blush.Finder{
+				blush.NewExact("ERROR", blush.Red),
+				blush.NewExact("38", blush.Blue),
+			},
+			"an " + blush.Colourise("ERROR", blush.Red) + " has occurred\n",
+		},
+		{
+			"letter m",
+			[]blush.Finder{
+				blush.NewExact("ERROR", blush.Red),
+				blush.NewExact("m", blush.Blue),
+			},
+			"an " + blush.Colourise("ERROR", blush.Red) + " has occurred\n",
+		},
+		{
+			"regexp",
+			[]blush.Finder{
+				blush.NewExact("ERROR", blush.Red),
+				blush.NewRx(regexp.MustCompile(`\d+;\d+`), blush.Blue),
+			},
+			"an " + blush.Colourise("ERROR", blush.Red) + " has occurred\n",
+		},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			b := &blush.Blush{
+				Reader:  io.NopCloser(bytes.NewBufferString(input)),
+				Finders: tc.finders,
+			}
+			buf := &bytes.Buffer{}
+			_, err := b.WriteTo(buf)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+}
+
+func testBlushWriteToOverlappingMatches(t *testing.T) {
+	t.Parallel()
+	input := "user=admin logged in\n"
+	tcs := []struct {
+		name    string
+		finders []blush.Finder
+		want    string
+	}{
+		{
+			"nested",
+			[]blush.Finder{
+				blush.NewExact("user=admin", blush.Green),
+				blush.NewExact("admin", blush.Red),
+			},
+			blush.Colourise("user=", blush.Green) + blush.Colourise("admin", blush.Red) + " logged in\n",
+		},
+		{
+			"inside",
+			[]blush.Finder{
+				blush.NewExact("admin", blush.Red),
+				blush.NewExact("user=admin", blush.Green),
+			},
+			blush.Colourise("user=admin", blush.Green) + " logged in\n",
+		},
+		{
+			"partial",
+			[]blush.Finder{
+				blush.NewExact("user=ad", blush.Green),
+				blush.NewExact("admin logged", blush.Red),
+			},
+			blush.Colourise("user=", blush.Green) + blush.Colourise("admin logged", blush.Red) + " in\n",
+		},
+		{
+			"no colour does not hide",
+			[]blush.Finder{
+				blush.NewExact("admin", blush.Red),
+				blush.NewExact("admin", blush.NoColour),
+			},
+			"user=" + blush.Colourise("admin", blush.Red) + " logged in\n",
+		},
+		{
+			"same colour",
+			[]blush.Finder{
+				blush.NewExact("user=", blush.Red),
+				blush.NewExact("admin", blush.Red),
+			},
+			blush.Colourise("user=admin", blush.Red) + " logged in\n",
+		},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			b := &blush.Blush{
+				Reader:  io.NopCloser(bytes.NewBufferString(input)),
+				Finders: tc.finders,
+			}
+			buf := &bytes.Buffer{}
+			_, err := b.WriteTo(buf)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, buf.String())
+			assert.EqualValues(t, strings.Count(buf.String(), "\033[38;"), strings.Count(buf.String(), "\033[0m"))
+		})
+	}
+}
+
+func testBlushWriteToInvert(t *testing.T) {
+	t.Parallel()
+	input := "GET /health\nGET /users\nPOST /health\nPOST /users\n"
+	tcs := []struct {
+		name    string
+		finders []blush.Finder
+		drop    bool
+		want    string
+	}{
+		{"one finder", []blush.Finder{blush.NewExact("health", blush.Blue)}, false, "GET /users\nPOST /users\n"},
+		{"with drop", []blush.Finder{blush.NewExact("health", blush.Blue)}, true, "GET /users\nPOST /users\n"},
+		{"two finders", []blush.Finder{
+			blush.NewExact("health", blush.Blue),
+			blush.NewExact("GET", blush.Red),
+		}, false, "POST /users\n"},
+		{"no match", []blush.Finder{blush.NewExact("DELETE", blush.Blue)}, false, input},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			b := &blush.Blush{
+				Reader:  io.NopCloser(bytes.NewBufferString(input)),
+				Finders: tc.finders,
+				Drop:    tc.drop,
+				Invert:  true,
+			}
+			buf := &bytes.Buffer{}
+			_, err := b.WriteTo(buf)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+}
+
+func testBlushWriteToExclude(t *testing.T) {
+	t.Parallel()
+	input := "GET /health\nGET /users\nPOST /health\nPOST /users\n"
+	health := blush.NewExact("health", blush.Red)
+	get := blush.NewExact("GET", blush.Blue)
+	users := blush.NewExact("users", blush.Blue)
+	tcs := []struct {
+		name    string
+		finders []blush.Finder
+		exclude []blush.Finder
+		drop    bool
+		want    string
+	}{
+		{"only exclude", nil, []blush.Finder{health}, false, "GET /users\nPOST /users\n"},
+		{"only exclude drop", nil, []blush.Finder{health}, true, "GET /users\nPOST /users\n"},
+		{"with finders", []blush.Finder{get}, []blush.Finder{health}, false,
+			blush.Colourise("GET", blush.Blue) + " /users\nPOST /users\n"},
+		{"with finders drop", []blush.Finder{get}, []blush.Finder{health}, true,
+			blush.Colourise("GET", blush.Blue) + " /users\n"},
+		{"two excludes", []blush.Finder{get}, []blush.Finder{health, users}, false, ""},
+		{"same as finder", []blush.Finder{health}, []blush.Finder{health}, false, "GET /users\nPOST /users\n"},
+		{"exclude regexp", nil, []blush.Finder{blush.NewRx(regexp.MustCompile("^POST"), blush.NoColour)}, false,
+			"GET /health\nGET /users\n"},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			b := &blush.Blush{
+				Reader:  io.NopCloser(bytes.NewBufferString(input)),
+				Finders: tc.finders,
+				Exclude: tc.exclude,
+				Drop:    tc.drop,
+			}
+			buf := &bytes.Buffer{}
+			_, err := b.WriteTo(buf)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+}
+
+func testBlushWriteToContext(t *testing.T) {
+	t.Parallel()
+	input := "a\nb\nc\nMATCH 1\nd\ne\nf\ng\nMATCH 2\nh\nMATCH 3\ni\n"
+	tcs := []struct {
+		name   string
+		before uint
+		after  uint
+		invert bool
+		want   string
+	}{
+		{"no context", 0, 0, false, "MATCH 1\nMATCH 2\nMATCH 3\n"},
+		{"before", 1, 0, false, "c\nMATCH 1\n--\ng\nMATCH 2\nh\nMATCH 3\n"},
+		{"before many", 2, 0, false, "b\nc\nMATCH 1\n--\nf\ng\nMATCH 2\nh\nMATCH 3\n"},
+		{"before all", 3, 0, false, "a\nb\nc\nMATCH 1\n--\ne\nf\ng\nMATCH 2\nh\nMATCH 3\n"},
+		{"before touching", 4, 0, false, "a\nb\nc\nMATCH 1\nd\ne\nf\ng\nMATCH 2\nh\nMATCH 3\n"},
+		{"after", 0, 1, false, "MATCH 1\nd\n--\nMATCH 2\nh\nMATCH 3\ni\n"},
+		{"after many", 0, 3, false, "MATCH 1\nd\ne\nf\n--\nMATCH 2\nh\nMATCH 3\ni\n"},
+		{"both", 1, 1, false, "c\nMATCH 1\nd\n--\ng\nMATCH 2\nh\nMATCH 3\ni\n"},
+		{"both touching", 2, 2, false, "b\nc\nMATCH 1\nd\ne\nf\ng\nMATCH 2\nh\nMATCH 3\ni\n"},
+		{"invert", 0, 1, true, "a\nb\nc\nMATCH 1\nd\ne\nf\ng\nMATCH 2\nh\nMATCH 3\ni\n"},
+		{"invert no context", 0, 0, true, "a\nb\nc\nd\ne\nf\ng\nh\ni\n"},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			b := &blush.Blush{
+				Reader:        io.NopCloser(bytes.NewBufferString(input)),
+				Finders:       []blush.Finder{blush.NewExact("MATCH", blush.Blue)},
+				Renderer:      blush.PlainRenderer{},
+				Drop:          true,
+				Invert:        tc.invert,
+				ContextBefore: tc.before,
+				ContextAfter:  tc.after,
+			}
+			buf := &bytes.Buffer{}
+			_, err := b.WriteTo(buf)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+
+	t.Run("exclude", func(t *testing.T) {
+		b := &blush.Blush{
+			Reader:       io.NopCloser(bytes.NewBufferString(input)),
+			Exclude:      []blush.Finder{blush.NewExact("MATCH", blush.Blue)},
+			Renderer:     blush.PlainRenderer{},
+			ContextAfter: 1,
+		}
+		buf := &bytes.Buffer{}
+		_, err := b.WriteTo(buf)
+		assert.NoError(t, err)
+		assert.Equal(t, "a\nb\nc\nMATCH 1\nd\ne\nf\ng\nMATCH 2\nh\nMATCH 3\ni\n", buf.String())
+	})
+}
+
+func testBlushWriteToContextStyle(t *testing.T) {
+	t.Parallel()
+	b := &blush.Blush{
+		Reader:        io.NopCloser(bytes.NewBufferString("before\r\nMATCH\nafter")),
+		Finders:       []blush.Finder{blush.NewExact("MATCH", blush.Blue)},
+		Drop:          true,
+		ContextBefore: 1,
+		ContextAfter:  1,
+	}
+	buf := &bytes.Buffer{}
+	_, err := b.WriteTo(buf)
+	assert.NoError(t, err)
+	want := blush.Stylise("before", blush.ContextStyle) + "\r\n" +
+		blush.Colourise("MATCH", blush.Blue) + "\n" +
+		blush.Stylise("after", blush.ContextStyle)
+	assert.Equal(t, want, buf.String())
+}
+
+func testBlushWriteToPositions(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name       string
+		fileName   bool
+		lineNumber bool
+		byteOffset bool
+		drop       bool
+		want       string
+	}{
+		{"none", false, false, false, true, "MATCH 1\nMATCH 2\nMATCH 3\n"},
+		{"file name", true, false, false, true, "one: MATCH 1\ntwo: MATCH 2\nMATCH 3\n"},
+		{"line number", false, true, false, true, "2: MATCH 1\n1: MATCH 2\n2: MATCH 3\n"},
+		{"byte offset", false, false, true, true, "4: MATCH 1\n0: MATCH 2\n5: MATCH 3\n"},
+		{"all", true, true, true, true, "one:2:4: MATCH 1\ntwo:1:0: MATCH 2\n2:5: MATCH 3\n"},
+		{"no drop", false, true, false, false,
+			"1: aaa\n2: MATCH 1\n3: bbb\n1: MATCH 2\n2: ccc\n1: ddd\r\n2: MATCH 3\n"},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			r, err := reader.NewMultiReader(
+				reader.WithReader("one", io.NopCloser(bytes.NewBufferString("aaa\nMATCH 1\nbbb"))),
+				reader.WithReader("two", io.NopCloser(bytes.NewBufferString("MATCH 2\nccc\n"))),
+				reader.WithReader("", io.NopCloser(bytes.NewBufferString("ddd\r\nMATCH 3\n"))),
+			)
+			assert.NoError(t, err)
+			b := &blush.Blush{
+				Reader:       r,
+				Finders:      []blush.Finder{blush.NewExact("MATCH", blush.Blue)},
+				Renderer:     blush.PlainRenderer{},
+				Drop:         tc.drop,
+				WithFileName: tc.fileName,
+				LineNumber:   tc.lineNumber,
+				ByteOffset:   tc.byteOffset,
+			}
+			buf := &bytes.Buffer{}
+			_, err = b.WriteTo(buf)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+
+	t.Run("context", func(t *testing.T) {
+		b := &blush.Blush{
+			Reader:        io.NopCloser(bytes.NewBufferString("aaa\nbbb\nMATCH\nccc\n")),
+			Finders:       []blush.Finder{blush.NewExact("MATCH", blush.Blue)},
+			Renderer:      blush.PlainRenderer{},
+			Drop:          true,
+			LineNumber:    true,
+			ContextBefore: 1,
+			ContextAfter:  1,
+		}
+		buf := &bytes.Buffer{}
+		_, err := b.WriteTo(buf)
+		assert.NoError(t, err)
+		assert.Equal(t, "2: bbb\n3: MATCH\n4: ccc\n", buf.String())
+	})
+}
+
+func testBlushWriteToOnlyMatching(t *testing.T) {
+	t.Parallel()
+	input := "req=abc123 ip=10.0.0.1\nnothing here\nREQ=def456 ip=10.0.0.2\n"
+	rx := func(s string) blush.Finder { return blush.NewRx(regexp.MustCompile(s), blush.Red) }
+	tcs := []struct {
+		name    string
+		finders []blush.Finder
+		want    string
+	}{
+		{"exact", []blush.Finder{blush.NewExact("ip", blush.Blue)}, "ip\nip\n"},
+		{"iexact", []blush.Finder{blush.NewIexact("req", blush.Blue)}, "req\nREQ\n"},
+		{"rx", []blush.Finder{rx(`\d+\.\d+\.\d+\.\d+`)}, "10.0.0.1\n10.0.0.2\n"},
+		{"many in line", []blush.Finder{rx(`\d+`)}, "123\n10\n0\n0\n1\n456\n10\n0\n0\n2\n"},
+		{"in order", []blush.Finder{rx(`ip=\S+`), rx(`(?i)req=\w+`)},
+			"req=abc123\nip=10.0.0.1\nREQ=def456\nip=10.0.0.2\n"},
+		{"overlapping", []blush.Finder{rx(`abc\d+`), rx(`\d+ ip`)}, "abc123\n456 ip\n"},
+		{"empty matches", []blush.Finder{rx(`x*`)}, ""},
+		{"new line", []blush.Finder{rx(`\d\n`)}, "1\n2\n"},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			b := &blush.Blush{
+				Reader:       io.NopCloser(bytes.NewBufferString(input)),
+				Finders:      tc.finders,
+				Renderer:     blush.PlainRenderer{},
+				OnlyMatching: true,
+			}
+			buf := &bytes.Buffer{}
+			_, err := b.WriteTo(buf)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+
+	t.Run("colour and prefix", func(t *testing.T) {
+		r, err := reader.NewMultiReader(reader.WithReader("name", io.NopCloser(bytes.NewBufferString(input))))
+		assert.NoError(t, err)
+		b := &blush.Blush{
+			Reader:        r,
+			Finders:       []blush.Finder{blush.NewIexact("req", blush.Blue)},
+			OnlyMatching:  true,
+			WithFileName:  true,
+			LineNumber:    true,
+			ByteOffset:    true,
+			ContextBefore: 1,
+		}
+		buf := &bytes.Buffer{}
+		_, err = b.WriteTo(buf)
+		assert.NoError(t, err)
+		want := "name:1:0: " + blush.Colourise("req", blush.Blue) + "\n" +
+			"name:3:36: " + blush.Colourise("REQ", blush.Blue) + "\n"
+		assert.Equal(t, want, buf.String())
+	})
+
+	t.Run("invert", func(t *testing.T) {
+		b := &blush.Blush{
+			Reader:       io.NopCloser(bytes.NewBufferString(input)),
+			Finders:      []blush.Finder{blush.NewExact("ip", blush.Blue)},
+			OnlyMatching: true,
+			Invert:       true,
+		}
+		buf := &bytes.Buffer{}
+		_, err := b.WriteTo(buf)
+		assert.NoError(t, err)
+		assert.Empty(t, buf.String())
+	})
+}
+
+func testBlushWriteToCount(t *testing.T) {
+	t.Parallel()
+	getReader := func() io.ReadCloser {
+		r, err := reader.NewMultiReader(
+			reader.WithReader("one", io.NopCloser(bytes.NewBufferString("aaa\nMATCH\nbbb MATCH MATCH\n"))),
+			reader.WithReader("two", io.NopCloser(bytes.NewBufferString("ccc\n"))),
+			reader.WithReader("", io.NopCloser(bytes.NewBufferString("MATCH"))),
+		)
+		assert.NoError(t, err)
+		return r
+	}
+	tcs := []struct {
+		name     string
+		b        *blush.Blush
+		fileName bool
+		want     string
+	}{
+		{"count", &blush.Blush{}, false, "2\n0\n1\n"},
+		{"file name", &blush.Blush{WithFileName: true}, false, "one: 2\ntwo: 0\n1\n"},
+		{"drop", &blush.Blush{Drop: true, LineNumber: true}, false, "2\n0\n1\n"},
+		{"invert", &blush.Blush{Invert: true}, false, "1\n1\n0\n"},
+		{"exclude", &blush.Blush{Exclude: []blush.Finder{blush.NewExact("bbb", blush.NoColour)}}, false, "1\n0\n1\n"},
+		{"only matching", &blush.Blush{OnlyMatching: true}, false, "2\n0\n1\n"},
+		{"context", &blush.Blush{ContextAfter: 2}, false, "2\n0\n1\n"},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			b := tc.b
+			b.Reader = getReader()
+			b.Finders = []blush.Finder{blush.NewExact("MATCH", blush.Blue)}
+			b.Renderer = blush.PlainRenderer{}
+			b.Count = true
+			buf := &bytes.Buffer{}
+			_, err := b.WriteTo(buf)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+
+	t.Run("no sources", func(t *testing.T) {
+		b := &blush.Blush{
+			Reader:  io.NopCloser(bytes.NewBufferString("MATCH\naaa\nMATCH\n")),
+			Finders: []blush.Finder{blush.NewExact("MATCH", blush.Blue)},
+			Count:   true,
+		}
+		buf := &bytes.Buffer{}
+		_, err := b.WriteTo(buf)
+		assert.NoError(t, err)
+		assert.Equal(t, "2\n", buf.String())
+	})
+}
+
+func testBlushWriteToStats(t *testing.T) {
+	t.Parallel()
+	input := "aaa bbb aaa\nccc\naaa\n"
+	r, err := reader.NewMultiReader(
+		reader.WithReader("one", io.NopCloser(bytes.NewBufferString(input))),
+		reader.WithReader("two", io.NopCloser(bytes.NewBufferString("bbb"))),
+	)
+	assert.NoError(t, err)
+	aaa := blush.NewExact("aaa", blush.Blue)
+	bbb := blush.NewRx(regexp.MustCompile("b+"), blush.Red)
+	ddd := blush.NewExact("ddd", blush.Red)
+	other := &finderMock{find: func(s string) (string, bool) {
+		return s, strings.Contains(s, "ccc")
+	}}
+	b := &blush.Blush{
+		Reader:  r,
+		Finders: []blush.Finder{aaa, bbb, ddd, other},
+		Drop:    true,
+	}
+	_, err = b.WriteTo(io.Discard)
+	assert.NoError(t, err)
+
+	s := b.Stats()
+	assert.Equal(t, []blush.FinderStats{
+		{Finder: aaa, Matches: 3, Lines: 2},
+		{Finder: bbb, Matches: 2, Lines: 2},
+		{Finder: ddd, Matches: 0, Lines: 0},
+		{Finder: other, Matches: 1, Lines: 1},
+	}, s.Finders)
+	assert.Equal(t, 2, s.Files)
+	assert.Equal(t, 4, s.Lines)
+	assert.EqualValues(t, len(input)+3, s.Bytes)
+	assert.NotZero(t, s.Elapsed)
+	assert.Equal(t, 4, s.Matched)
+
+	s.Finders[0].Matches = 100
+	assert.Equal(t, 3, b.Stats().Finders[0].Matches)
+}
+
+func testBlushWriteToMatched(t *testing.T) {
+	t.Parallel()
+	input := "aaa MATCH\nbbb\nMATCH ccc\nddd\n"
+	match := blush.NewExact("MATCH", blush.Blue)
+	none := blush.NewExact("NONE", blush.Blue)
+	tcs := []struct {
+		name string
+		b    *blush.Blush
+		want int
+	}{
+		{"no cut", &blush.Blush{Finders: []blush.Finder{match}}, 2},
+		{"drop", &blush.Blush{Finders: []blush.Finder{match}, Drop: true}, 2},
+		{"no match", &blush.Blush{Finders: []blush.Finder{none}}, 0},
+		{"invert", &blush.Blush{Finders: []blush.Finder{match}, Invert: true}, 2},
+		{"invert no match", &blush.Blush{Finders: []blush.Finder{none}, Invert: true}, 4},
+		{"exclude", &blush.Blush{
+			Finders: []blush.Finder{match},
+			Exclude: []blush.Finder{blush.NewExact("ccc", blush.NoColour)},
+		}, 1},
+		{"only exclude", &blush.Blush{
+			Exclude: []blush.Finder{blush.NewExact("ccc", blush.NoColour)},
+		}, 3},
+		{"only matching", &blush.Blush{Finders: []blush.Finder{match}, OnlyMatching: true}, 2},
+		{"count", &blush.Blush{Finders: []blush.Finder{match}, Count: true}, 2},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			b := tc.b
+			b.Reader = io.NopCloser(bytes.NewBufferString(input))
+			_, err := b.WriteTo(io.Discard)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, b.Stats().Matched)
+		})
+	}
+}
+
+func testBlushWriteToQuiet(t *testing.T) {
+	t.Parallel()
+	var opened []string
+	source := func(name, input string) io.ReadCloser {
+		return nopCloser{
+			Reader: bytes.NewBufferString(input),
+			closeFunc: func() error {
+				opened = append(opened, name)
+				return nil
+			},
+		}
+	}
+	r, err := reader.NewMultiReader(
+		reader.WithReader("one", source("one", "aaa\nbbb\n")),
+		reader.WithReader("two", source("two", "MATCH\nccc\nMATCH\n")),
+		reader.WithReader("three", source("three", "MATCH\n")),
+	)
+	assert.NoError(t, err)
+	b := &blush.Blush{
+		Reader:  r,
+		Finders: []blush.Finder{blush.NewExact("MATCH", blush.Blue)},
+		Count:   true,
+		Quiet:   true,
+	}
+	buf := &bytes.Buffer{}
+	_, err = b.WriteTo(buf)
+	assert.NoError(t, err)
+	assert.Empty(t, buf.String())
+	s := b.Stats()
+	assert.Equal(t, 1, s.Matched)
+	assert.Equal(t, 2, s.Files)
+	assert.Equal(t, 3, s.Lines)
+	assert.Equal(t, []string{"one", "two"}, opened)
+
+	err = b.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"one", "two"}, opened)
+
+	b = &blush.Blush{
+		Reader:  io.NopCloser(bytes.NewBufferString("aaa\nbbb\n")),
+		Finders: []blush.Finder{blush.NewExact("MATCH", blush.Blue)},
+		Quiet:   true,
+	}
+	_, err = b.WriteTo(buf)
+	assert.NoError(t, err)
+	assert.Empty(t, buf.String())
+	assert.Zero(t, b.Stats().Matched)
+}
+
+func testBlushWriteToReadError(t *testing.T) {
+	t.Parallel()
+	e := errors.New("read error")
+	b := &blush.Blush{
+		Reader: io.NopCloser(&badReader{
+			r:   bytes.NewBufferString("MATCH\naaa"),
+			err: e,
+		}),
+		Finders: []blush.Finder{blush.NewExact("MATCH", blush.NoColour)},
+		Drop:    true,
+	}
+	buf := &bytes.Buffer{}
+	_, err := b.WriteTo(buf)
+	assert.True(t, errors.Is(err, e))
+	assert.Equal(t, "MATCH\n", buf.String())
+
+	r, err := reader.NewMultiReader(
+		reader.WithReader("one", nopCloser{
+			Reader:    bytes.NewBufferString("MATCH one\n"),
+			closeFunc: func() error { return e },
+		}),
+		reader.WithReader("two", io.NopCloser(bytes.NewBufferString("MATCH two\n"))),
+	)
+	assert.NoError(t, err)
+	b = &blush.Blush{
+		Reader:   r,
+		Finders:  []blush.Finder{blush.NewExact("MATCH", blush.NoColour)},
+		Renderer: blush.PlainRenderer{},
+	}
+	buf.Reset()
+	_, err = b.WriteTo(buf)
+	assert.True(t, errors.Is(err, e))
+	assert.Equal(t, "MATCH one\nMATCH two\n", buf.String())
+}
+
+func testBlushWriteToFiles(t *testing.T) {
+	t.Parallel()
+	getReader := func() io.ReadCloser {
+		r, err := reader.NewMultiReader(
+			reader.WithReader("one", io.NopCloser(bytes.NewBufferString("aaa\nMATCH\nbbb\nMATCH\n"))),
+			reader.WithReader("two", io.NopCloser(bytes.NewBufferString("ccc\n"))),
+			reader.WithReader("", io.NopCloser(bytes.NewBufferString("MATCH"))),
+			reader.WithReader("three", io.NopCloser(bytes.NewBufferString("MATCH bbb\n"))),
+		)
+		assert.NoError(t, err)
+		return r
+	}
+	tcs := []struct {
+		name       string
+		b          *blush.Blush
+		want       string
+		wantLines  int
+		wantListed int
+	}{
+		{"with matches", &blush.Blush{FilesWithMatches: true}, "one\n(standard input)\nthree\n", 5, 3},
+		{"without match", &blush.Blush{FilesWithoutMatch: true}, "two\n", 5, 1},
+		{"invert", &blush.Blush{FilesWithMatches: true, Invert: true}, "one\ntwo\n", 4, 2},
+		{"exclude", &blush.Blush{
+			FilesWithoutMatch: true,
+			Exclude:           []blush.Finder{blush.NewExact("bbb", blush.NoColour)},
+		}, "two\nthree\n", 5, 2},
+		{"count", &blush.Blush{FilesWithMatches: true, Count: true, ContextAfter: 2}, "one\n(standard input)\nthree\n", 5, 3},
+		{"quiet", &blush.Blush{FilesWithoutMatch: true, Quiet: true}, "", 5, 1},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			b := tc.b
+			b.Reader = getReader()
+			b.Finders = []blush.Finder{blush.NewExact("MATCH", blush.Blue)}
+			b.Renderer = blush.PlainRenderer{}
+			buf := &bytes.Buffer{}
+			_, err := b.WriteTo(buf)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, buf.String())
+			assert.Equal(t, tc.wantLines, b.Stats().Lines)
+			assert.Equal(t, tc.wantListed, b.Stats().Listed)
+		})
+	}
+}
+
+func testBlushWriteToMaxCount(t *testing.T) {
+	t.Parallel()
+	var closed []string
+	source := func(name, input string) io.ReadCloser {
+		return nopCloser{
+			Reader: bytes.NewBufferString(input),
+			closeFunc: func() error {
+				closed = append(closed, name)
+				return nil
+			},
+		}
+	}
+	getReader := func() io.ReadCloser {
+		r, err := reader.NewMultiReader(
+			reader.WithReader("one", source("one", "MATCH 1\naaa\nMATCH 2\nbbb\nMATCH 3\n")),
+			reader.WithReader("two", source("two", "ccc\nMATCH 4\nMATCH 5\n")),
+			reader.WithReader("three", source("three", "MATCH 6\n")),
+		)
+		assert.NoError(t, err)
+		return r
+	}
+	tcs := []struct {
+		name       string
+		b          *blush.Blush
+		want       string
+		wantLines  int
+		wantClosed []string
+	}{
+		{"per source", &blush.Blush{MaxCount: 1}, "MATCH 1\nMATCH 4\nMATCH 6\n", 4, []string{"one", "two", "three"}},
+		{"per source more", &blush.Blush{MaxCount: 2}, "MATCH 1\nMATCH 2\nMATCH 4\nMATCH 5\nMATCH 6\n", 7, []string{"one", "two", "three"}},
+		{"total", &blush.Blush{MaxTotal: 4}, "MATCH 1\nMATCH 2\nMATCH 3\nMATCH 4\n", 7, []string{"one", "two"}},
+		{"both", &blush.Blush{MaxCount: 2, MaxTotal: 3}, "MATCH 1\nMATCH 2\nMATCH 4\n", 5, []string{"one", "two"}},
+		{"after context", &blush.Blush{MaxCount: 1, ContextAfter: 2}, "MATCH 1\naaa\nMATCH 2\n--\nMATCH 4\nMATCH 5\nMATCH 6\n", 7, []string{"one", "two", "three"}},
+		{"count", &blush.Blush{MaxCount: 2, Count: true}, "2\n2\n1\n", 7, []string{"one", "two", "three"}},
+		{"invert", &blush.Blush{MaxTotal: 2, Invert: true}, "aaa\nbbb\n", 4, []string{"one"}},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			closed = nil
+			b := tc.b
+			b.Reader = getReader()
+			b.Finders = []blush.Finder{blush.NewExact("MATCH", blush.NoColour)}
+			b.Renderer = blush.PlainRenderer{}
+			b.Drop = true
+			buf := &bytes.Buffer{}
+			_, err := b.WriteTo(buf)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, buf.String())
+			assert.Equal(t, tc.wantLines, b.Stats().Lines)
+			assert.Equal(t, tc.wantClosed, closed)
+		})
+	}
+
+	t.Run("no sources", func(t *testing.T) {
+		b := &blush.Blush{
+			Reader:   io.NopCloser(bytes.NewBufferString("MATCH\naaa\nMATCH\nMATCH\n")),
+			Finders:  []blush.Finder{blush.NewExact("MATCH", blush.NoColour)},
+			Renderer: blush.PlainRenderer{},
+			MaxCount: 2,
+		}
+		buf := &bytes.Buffer{}
+		_, err := b.WriteTo(buf)
+		assert.NoError(t, err)
+		assert.Equal(t, "MATCH\naaa\nMATCH\n", buf.String())
+		assert.Equal(t, 3, b.Stats().Lines)
+	})
+}
+
+func testBlushWriteToBinaryFiles(t *testing.T) {
+	t.Parallel()
+	getReader := func() io.ReadCloser {
+		r, err := reader.NewMultiReader(
+			reader.WithReader("one", io.NopCloser(bytes.NewBufferString("aaa\x00\nMATCH 1\nMATCH 2\n"))),
+			reader.WithReader("two", io.NopCloser(bytes.NewBufferString("MATCH 3\nbbb\n"))),
+			reader.WithReader("three", io.NopCloser(bytes.NewBufferString("\x00ccc\n"))),
+		)
+		assert.NoError(t, err)
+		return r
+	}
+	tcs := []struct {
+		name      string
+		b         *blush.Blush
+		want      string
+		wantFiles int
+	}{
+		{"default", &blush.Blush{}, "Binary file one matches\nMATCH 3\nbbb\n", 3},
+		{"text", &blush.Blush{BinaryFiles: blush.BinaryText}, "aaa\x00\nMATCH 1\nMATCH 2\nMATCH 3\nbbb\n\x00ccc\n", 3},
+		{"text drop", &blush.Blush{BinaryFiles: blush.BinaryText, Drop: true}, "MATCH 1\nMATCH 2\nMATCH 3\n", 3},
+		{"match", &blush.Blush{BinaryFiles: blush.BinaryMatch}, "Binary file one matches\nMATCH 3\nbbb\n", 3},
+		{"match invert", &blush.Blush{BinaryFiles: blush.BinaryMatch, Invert: true}, "Binary file one matches\nbbb\nBinary file three matches\n", 3},
+		{"match count", &blush.Blush{BinaryFiles: blush.BinaryMatch, Count: true}, "2\n1\n0\n", 3},
+		{"match files", &blush.Blush{BinaryFiles: blush.BinaryMatch, FilesWithMatches: true}, "one\ntwo\n", 3},
+		{"skip", &blush.Blush{BinaryFiles: blush.BinarySkip}, "MATCH 3\nbbb\n", 1},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			b := tc.b
+			b.Reader = getReader()
+			b.Finders = []blush.Finder{blush.NewExact("MATCH", blush.NoColour)}
+			b.Renderer = blush.PlainRenderer{}
+			buf := &bytes.Buffer{}
+			_, err := b.WriteTo(buf)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, buf.String())
+			assert.Equal(t, tc.wantFiles, b.Stats().Files)
+		})
+	}
+
+	t.Run("no sources", func(t *testing.T) {
+		b := &blush.Blush{
+			Reader:      io.NopCloser(bytes.NewBufferString("\x00\nMATCH\n")),
+			Finders:     []blush.Finder{blush.NewExact("MATCH", blush.NoColour)},
+			Renderer:    blush.PlainRenderer{},
+			BinaryFiles: blush.BinaryMatch,
+		}
+		buf := &bytes.Buffer{}
+		_, err := b.WriteTo(buf)
+		assert.NoError(t, err)
+		assert.Equal(t, "Binary file (standard input) matches\n", buf.String())
+	})
+}
+
 func testBlushClosesReader(t *testing.T) {
 	t.Parallel()
 	var called bool
@@ -745,3 +1538,53 @@ func testBlushReadWriteToMode(t *testing.T) {
 	_, err = b.Read(p)
 	assert.True(t, errors.Is(err, blush.ErrReadWriteMix))
 }
+
+func testBlushWriteToFollow(t *testing.T) {
+	t.Parallel()
+	r := &followReader{sources: [][2]string{
+		{"one", "aaa\nMATCH 1\n"},
+		{"two", "MATCH 2\n"},
+		{"one", "bbb\nMATCH 3\n"},
+		{"two", "MATCH 4\n"},
+	}}
+	b := &blush.Blush{
+		Reader:       r,
+		Finders:      []blush.Finder{blush.NewExact("MATCH", blush.Blue)},
+		Renderer:     blush.PlainRenderer{},
+		Drop:         true,
+		WithFileName: true,
+		LineNumber:   true,
+		ByteOffset:   true,
+	}
+	buf := &bytes.Buffer{}
+	_, err := b.WriteTo(buf)
+	assert.NoError(t, err)
+	want := "one:2:4: MATCH 1\ntwo:1:0: MATCH 2\none:4:16: MATCH 3\ntwo:2:8: MATCH 4\n"
+	assert.Equal(t, want, buf.String())
+	assert.Equal(t, 2, b.Stats().Files)
+}
+
+func testBlushWriteToFollowMaxCount(t *testing.T) {
+	t.Parallel()
+	r := &followReader{sources: [][2]string{
+		{"one", "MATCH 1\nMATCH 2\n"},
+		{"two", "MATCH 3\n"},
+		{"one", "MATCH 4\n"},
+		{"two", "aaa\nMATCH 5\nMATCH 6\n"},
+	}}
+	b := &blush.Blush{
+		Reader:       r,
+		Finders:      []blush.Finder{blush.NewExact("MATCH", blush.Blue)},
+		Renderer:     blush.PlainRenderer{},
+		Drop:         true,
+		WithFileName: true,
+		LineNumber:   true,
+		MaxCount:     2,
+	}
+	buf := &bytes.Buffer{}
+	_, err := b.WriteTo(buf)
+	assert.NoError(t, err)
+	want := "one:1: MATCH 1\none:2: MATCH 2\ntwo:1: MATCH 3\ntwo:3: MATCH 5\n"
+	assert.Equal(t, want, buf.String())
+	assert.Equal(t, 4, b.Stats().Matched)
+}
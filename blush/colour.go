@@ -47,6 +47,52 @@ var (
 // DefaultColour is the default colour if no colour is set via arguments.
 var DefaultColour = Blue
 
+// ColourDepth is the number of colours a terminal can show.
+type ColourDepth int
+
+// These are the colour depths blush can render. The zero value means the depth
+// is not known.
+const (
+	Depth16         ColourDepth = 16
+	Depth256        ColourDepth = 256
+	DepthTrueColour ColourDepth = 1 << 24
+)
+
+// ParseColourDepth returns the ColourDepth of the depth argument, which can be
+// 16, 256, truecolor, truecolour or 24bit.
+func ParseColourDepth(depth string) (ColourDepth, error) {
+	switch strings.ToLower(depth) {
+	case "16":
+		return Depth16, nil
+	case "256":
+		return Depth256, nil
+	case "truecolor", "truecolour", "24bit": // nolint:misspell // it's ok.
+		return DepthTrueColour, nil
+	}
+	return 0, fmt.Errorf("%w: %q", ErrColourDepth, depth)
+}
+
+// DetectColourDepth returns the colour depth of the terminal based on the
+// COLORTERM and TERM environment variables, which are looked up with getenv.
+// It returns Depth256 if it can't find any clues.
+func DetectColourDepth(getenv func(string) string) ColourDepth {
+	switch strings.ToLower(getenv("COLORTERM")) {
+	case "truecolor", "24bit": // nolint:misspell // it's ok.
+		return DepthTrueColour
+	}
+	term := strings.ToLower(getenv("TERM"))
+	switch {
+	case strings.HasSuffix(term, "-direct"):
+		return DepthTrueColour
+	case strings.Contains(term, "256"):
+		return Depth256
+	case term == "linux", term == "ansi", term == "cygwin",
+		strings.HasPrefix(term, "vt"), strings.HasPrefix(term, "cons"):
+		return Depth16
+	}
+	return Depth256
+}
+
 // RGB represents colours that can be printed in terminals. R, G and B should be
 // between 0 and 255.
 type RGB struct {
@@ -61,18 +107,71 @@ type Colour struct {
 
 // Colourise wraps the input between colours.
 func Colourise(input string, c Colour) string {
-	if c.Background == NoRGB && c.Foreground == NoRGB {
-		return input
-	}
+	return Stylise(input, c.Style())
+}
 
-	var fg, bg string
-	if c.Foreground != NoRGB {
-		fg = foreground(c.Foreground)
+// styliseTrue is like Stylise, but uses 24-bit colours.
+func styliseTrue(input string, s Style) string {
+	return stylise(input, s, foregroundTrue, backgroundTrue)
+}
+
+func foregroundTrue(c RGB) string {
+	return fmt.Sprintf("\033[38;2;%d;%d;%dm", c.R, c.G, c.B)
+}
+
+func backgroundTrue(c RGB) string {
+	return fmt.Sprintf("\033[48;2;%d;%d;%dm", c.R, c.G, c.B)
+}
+
+// stylise16 is like Stylise, but uses the closest colours of the basic 16
+// colour palette.
+func stylise16(input string, s Style) string {
+	return stylise(input, s, foreground16, background16)
+}
+
+func foreground16(c RGB) string {
+	return fmt.Sprintf("\033[%dm", 30+basic(c))
+}
+
+func background16(c RGB) string {
+	return fmt.Sprintf("\033[%dm", 40+basic(c))
+}
+
+// basic returns the offset of the closest colour to c in the 16 colour
+// palette. The channels that are at least half as bright as the brightest one
+// are turned on, and very bright colours are shown in their bright variant.
+// The return value should be added to 30 for foreground and 40 for background
+// colours.
+func basic(c RGB) int {
+	brightest := c.R
+	if c.G > brightest {
+		brightest = c.G
+	}
+	if c.B > brightest {
+		brightest = c.B
 	}
-	if c.Background != NoRGB {
-		bg = background(c.Background)
+	if brightest < 32 {
+		return 0
 	}
-	return fg + bg + input + unformat()
+	var code int
+	if c.R*2 >= brightest {
+		code |= 1
+	}
+	if c.G*2 >= brightest {
+		code |= 2
+	}
+	if c.B*2 >= brightest {
+		code |= 4
+	}
+	if brightest > 191 {
+		code += 60
+	}
+	return code
+}
+
+// hex returns the colour in the RRGGBB format.
+func hex(c RGB) string {
+	return fmt.Sprintf("%02x%02x%02x", c.R, c.G, c.B)
 }
 
 func foreground(c RGB) string {
@@ -95,25 +194,65 @@ func baseColor(value, factor int) int {
 	return int(6*float64(value)/256) * factor
 }
 
+// ParseColour returns the Colour of the spec. The spec is in the FG[/BG]
+// format, where each part can be a stock colour in its short (b) or long
+// (blue) form, a colour group (b1), a hex colour (#666 or #666666) or
+// no-colour. The background is set to the foreground of the BG part, for
+// example "yl/bl" is yellow on black and "/#333" only has a grey background.
+// An empty spec returns the DefaultColour. It returns ErrInvalidColour if any
+// of the parts are not valid.
+func ParseColour(spec string) (Colour, error) {
+	if spec == "" {
+		return DefaultColour, nil
+	}
+	fg, bg, hasBg := strings.Cut(spec, "/")
+	c := NoColour
+	if fg != "" {
+		var ok bool
+		if c, ok = parseColour(fg); !ok {
+			return NoColour, fmt.Errorf("%w: %q", ErrInvalidColour, spec)
+		}
+	}
+	if hasBg && bg != "" {
+		b, ok := parseColour(bg)
+		if !ok {
+			return NoColour, fmt.Errorf("%w: %q", ErrInvalidColour, spec)
+		}
+		c.Background = b.Foreground
+	}
+	return c, nil
+}
+
+// colorFromArg is like ParseColour, but returns the DefaultColour if the
+// colour is not valid.
 func colorFromArg(colour string) Colour {
+	c, err := ParseColour(colour)
+	if err != nil {
+		return DefaultColour
+	}
+	return c
+}
+
+func parseColour(colour string) (Colour, bool) {
 	if strings.HasPrefix(colour, "#") {
 		return hexColour(colour)
 	}
 	if grouping.MatchString(colour) {
-		if c := colourGroup(colour); c != NoColour {
-			return c
-		}
+		return colourGroup(colour)
 	}
 	return stockColour(colour)
 }
 
-func colourGroup(colour string) Colour {
+func colourGroup(colour string) (Colour, bool) {
 	g := grouping.FindStringSubmatch(colour)
 	group, err := strconv.Atoi(g[2])
 	if err != nil {
-		return NoColour
+		return NoColour, false
+	}
+	c, ok := stockColour(g[1])
+	if !ok {
+		return NoColour, false
 	}
-	c := stockColour(g[1])
 	switch group % 8 {
 	case 0:
 		c.Background = BgRed
@@ -132,35 +271,34 @@ func colourGroup(colour string) Colour {
 	case 7:
 		c.Background = BgYellow
 	}
-	return c
+	return c, true
 }
 
-func stockColour(colour string) Colour {
-	c := DefaultColour
+func stockColour(colour string) (Colour, bool) {
 	switch colour {
 	case "r", "red":
-		c = Red
+		return Red, true
 	case "b", "blue":
-		c = Blue
+		return Blue, true
 	case "g", "green":
-		c = Green
+		return Green, true
 	case "bl", "black":
-		c = Black
+		return Black, true
 	case "w", "white":
-		c = White
+		return White, true
 	case "cy", "cyan":
-		c = Cyan
+		return Cyan, true
 	case "mg", "magenta":
-		c = Magenta
+		return Magenta, true
 	case "yl", "yellow":
-		c = Yellow
+		return Yellow, true
 	case "no-colour", "no-color": // nolint:misspell // it's ok.
-		c = NoColour
+		return NoColour, true
 	}
-	return c
+	return NoColour, false
 }
 
-func hexColour(colour string) Colour {
+func hexColour(colour string) (Colour, bool) {
 	var r, g, b int
 	colour = strings.TrimPrefix(colour, "#")
 	switch len(colour) {
@@ -175,14 +313,14 @@ func hexColour(colour string) Colour {
 		g = getInt(c[2] + c[3])
 		b = getInt(c[4] + c[5])
 	default:
-		return DefaultColour
+		return NoColour, false
 	}
 	for _, n := range []int{r, g, b} {
 		if n < 0 {
-			return DefaultColour
+			return NoColour, false
 		}
 	}
-	return Colour{RGB{R: r, G: g, B: b}, NoRGB}
+	return Colour{RGB{R: r, G: g, B: b}, NoRGB}, true
 }
 
 // getInt returns a number between 0-255 from a hex code. If the hex is not
@@ -1,6 +1,7 @@
 package blush_test
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -37,3 +38,148 @@ func TestColourise(t *testing.T) {
 	assert.Contains(t, got, "[48;")
 	assert.EqualValues(t, 1, strings.Count(got, "\033[0m"))
 }
+
+func TestParseColourDepth(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		input   string
+		want    blush.ColourDepth
+		wantErr bool
+	}{
+		{"16", blush.Depth16, false},
+		{"256", blush.Depth256, false},
+		{"truecolor", blush.DepthTrueColour, false},
+		{"truecolour", blush.DepthTrueColour, false},
+		{"TrueColor", blush.DepthTrueColour, false},
+		{"24bit", blush.DepthTrueColour, false},
+		{"", 0, true},
+		{"8", 0, true},
+		{"true", 0, true},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := blush.ParseColourDepth(tc.input)
+			if tc.wantErr {
+				assert.True(t, errors.Is(err, blush.ErrColourDepth))
+				assert.Contains(t, err.Error(), tc.input)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestDetectColourDepth(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name      string
+		colorterm string
+		term      string
+		want      blush.ColourDepth
+	}{
+		{"nothing", "", "", blush.Depth256},
+		{"colorterm truecolor", "truecolor", "xterm", blush.DepthTrueColour},
+		{"colorterm 24bit", "24bit", "", blush.DepthTrueColour},
+		{"colorterm other", "yes", "xterm-256color", blush.Depth256},
+		{"direct", "", "xterm-direct", blush.DepthTrueColour},
+		{"256", "", "screen-256color", blush.Depth256},
+		{"xterm", "", "xterm", blush.Depth256},
+		{"linux", "", "linux", blush.Depth16},
+		{"vt100", "", "vt100", blush.Depth16},
+		{"ansi", "", "ansi", blush.Depth16},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			env := map[string]string{"COLORTERM": tc.colorterm, "TERM": tc.term}
+			got := blush.DetectColourDepth(func(key string) string { return env[key] })
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestNewRenderer(t *testing.T) {
+	t.Parallel()
+	assert.IsType(t, blush.ANSI16Renderer{}, blush.NewRenderer(blush.Depth16))
+	assert.IsType(t, blush.ANSIRenderer{}, blush.NewRenderer(blush.Depth256))
+	assert.IsType(t, blush.TrueColourRenderer{}, blush.NewRenderer(blush.DepthTrueColour))
+	assert.Equal(t, blush.DefaultRenderer, blush.NewRenderer(0))
+}
+
+func TestANSI16Renderer(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name   string
+		colour blush.Colour
+		want   string
+	}{
+		{"no colour", blush.NoColour, "aaa"},
+		{"red", blush.Red, "\033[91maaa\033[0m"},
+		{"green", blush.Green, "\033[92maaa\033[0m"},
+		{"blue", blush.Blue, "\033[94maaa\033[0m"},
+		{"black", blush.Black, "\033[30maaa\033[0m"},
+		{"white", blush.White, "\033[97maaa\033[0m"},
+		{"yellow", blush.Yellow, "\033[93maaa\033[0m"},
+		{"grey", blush.Colour{Foreground: blush.RGB{R: 102, G: 102, B: 102}, Background: blush.NoRGB}, "\033[37maaa\033[0m"},
+		{"dark red", blush.Colour{Foreground: blush.RGB{R: 128, G: 20, B: 0}, Background: blush.NoRGB}, "\033[31maaa\033[0m"},
+		{"orange", blush.Colour{Foreground: blush.RGB{R: 255, G: 165, B: 0}, Background: blush.NoRGB}, "\033[93maaa\033[0m"},
+		{"background", blush.Colour{Foreground: blush.NoRGB, Background: blush.BgRed}, "\033[41maaa\033[0m"},
+		{"both", blush.Colour{Foreground: blush.FgCyan, Background: blush.BgMagenta}, "\033[96m\033[45maaa\033[0m"},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := blush.ANSI16Renderer{}.Render("aaa", []blush.Match{{Style: tc.colour.Style(), End: 3}})
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+// nolint:misspell // it's ok.
+func TestParseColour(t *testing.T) {
+	t.Parallel()
+	grey := blush.RGB{R: 51, G: 51, B: 51}
+	tcs := []struct {
+		name    string
+		spec    string
+		want    blush.Colour
+		wantErr bool
+	}{
+		{"empty", "", blush.DefaultColour, false},
+		{"stock", "red", blush.Red, false},
+		{"stock short", "yl", blush.Yellow, false},
+		{"no colour", "no-colour", blush.NoColour, false},
+		{"no colour american", "no-color", blush.NoColour, false},
+		{"hex short", "#333", blush.Colour{Foreground: grey, Background: blush.NoRGB}, false},
+		{"hex long", "#333333", blush.Colour{Foreground: grey, Background: blush.NoRGB}, false},
+		{"group", "b1", blush.Colour{Foreground: blush.FgBlue, Background: blush.BgBlue}, false},
+		{"background", "yl/bl", blush.Colour{Foreground: blush.FgYellow, Background: blush.FgBlack}, false},
+		{"background long", "yellow/black", blush.Colour{Foreground: blush.FgYellow, Background: blush.FgBlack}, false},
+		{"background hex", "#ff0/#333", blush.Colour{Foreground: blush.FgYellow, Background: grey}, false},
+		{"background only", "/#333", blush.Colour{Foreground: blush.NoRGB, Background: grey}, false},
+		{"empty background", "r/", blush.Red, false},
+		{"group background", "b1/r", blush.Colour{Foreground: blush.FgBlue, Background: blush.FgRed}, false},
+		{"no colour background", "r/no-colour", blush.Red, false},
+		{"typo", "gren", blush.NoColour, true},
+		{"bad hex", "#ggg", blush.NoColour, true},
+		{"bad hex length", "#aaaa", blush.NoColour, true},
+		{"bad group", "x1", blush.NoColour, true},
+		{"bad background", "r/gren", blush.NoColour, true},
+		{"bad foreground", "gren/r", blush.NoColour, true},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := blush.ParseColour(tc.spec)
+			if tc.wantErr {
+				assert.True(t, errors.Is(err, blush.ErrInvalidColour))
+				assert.Contains(t, err.Error(), tc.spec)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -13,7 +13,69 @@
 // The hex number should be in 3 or 6 part format (#aaaaaa or #aaa) and each
 // part will be translated to a number value between 0 and 255 when creating the
 // Colour instance. If any of hex parts are not between 00 and ff, it creates
-// the DefaultColour value.
+// the DefaultColour value. ParseColour parses the same values, and an optional
+// background colour after a slash, for example "yl/bl", and returns an error
+// instead.
+//
+// Exact, Iexact and Rx implement the Indexer interface, which reports the
+// location of each match in the line instead of a decorated string. Blush asks
+// all Indexers for their matches in the original line and paints them in one
+// go, therefore a finder never matches the escape sequences added for another
+// finder. When two matches overlap, the finder that comes later in the Finders
+// slice wins the overlapping part. The Find method of these finders is a
+// convenience around FindIndex.
+//
+// If Invert is set, Blush only prints the lines that none of the Finders
+// match. The lines that any of the Exclude finders match are never printed,
+// and the rest of the lines are coloured as usual.
+//
+// ContextBefore and ContextAfter keep the lines around the printed lines that
+// would be dropped otherwise. The context lines are painted with ContextStyle,
+// and the GroupSeparator is written between the groups of lines that are not
+// next to each other.
+//
+// LineNumber and ByteOffset add the position of each line in its file to the
+// prefix of the line. If the Reader is a MultiReader, each file is read on its
+// own, therefore the positions start from the beginning of every file. If the
+// MultiReader follows its files, the positions continue in the lines that are
+// appended to them.
+//
+// If OnlyMatching is set, Blush prints each match on its own line instead of
+// the whole line. Only the finders that implement Indexer are used in this
+// mode.
+//
+// If Count is set, Blush prints the number of matched lines of each source
+// instead of the lines. The Stats method returns the number of matches of each
+// Finder and the number of files, lines and bytes that are read.
+//
+// If FilesWithMatches or FilesWithoutMatch is set, Blush only prints the names
+// of the sources that have or don't have any selected lines. Each source is
+// read up to its first selected line.
+//
+// MaxCount and MaxTotal limit the number of the selected lines of each source
+// and of all sources. Blush stops reading a source, or the whole Reader, as
+// soon as the limit is reached.
+//
+// BinaryFiles decides how the sources that are not plain text are read. With
+// BinaryMatch, which is the default, a BinaryMatches notice is printed instead
+// of their lines. With BinarySkip they are not read at all, and with BinaryText
+// they are read as plain text.
+//
+// If Quiet is set, Blush doesn't print anything and stops reading at the first
+// selected line. The Matched field of the Stats tells if there was any.
+//
+// Finders can be created with a Colour or a Style. A Style is a Colour with
+// text attributes such as Bold and Underline.
+//
+// NewLocator falls back to the DefaultColour for colours it can't parse and to
+// Exact or Iexact for patterns that don't compile. NewLocatorE and ParseStyle
+// return an error instead. NewLocatorMode can force the search to be taken as a
+// literal string with ModeFixed, or as a regexp with ModeRegexp.
+//
+// The Renderer of Blush turns the matches into the output. ANSIRenderer, which
+// is the default, uses 256 colour escape sequences. TrueColourRenderer uses
+// 24-bit colours, HTMLRenderer escapes the lines and wraps the matches in span
+// elements, and PlainRenderer leaves the lines as they are.
 //
 // Important Notes
 //
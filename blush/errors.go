@@ -16,4 +16,18 @@ var (
 	// ErrReadWriteMix is returned when the Read and WriteTo are called on the
 	// same object.
 	ErrReadWriteMix = errors.New("you cannot mix Read and WriteTo calls")
+
+	// ErrColourDepth is returned when the colour depth is not one of the
+	// supported values.
+	ErrColourDepth = errors.New("invalid colour depth")
+
+	// ErrInvalidColour is returned when a colour spec can't be parsed.
+	ErrInvalidColour = errors.New("invalid colour")
+
+	// ErrInvalidAttribute is returned when a text attribute can't be parsed.
+	ErrInvalidAttribute = errors.New("invalid attribute")
+
+	// ErrInvalidPattern is returned when a pattern looks like a regular
+	// expression but can't be compiled.
+	ErrInvalidPattern = errors.New("invalid pattern")
 )
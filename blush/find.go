@@ -4,10 +4,11 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"unicode/utf8"
 )
 
 var (
-	isRegExp = regexp.MustCompile(`[\^\$.\{\}\[\]\*\?]`)
+	isRegExp = regexp.MustCompile(`[\^\$.\{\}\[\]\*\?|()]`)
 	// This is used for matching colour groups (b1, etc.).
 	grouping = regexp.MustCompile(`^([[:alpha:]]+)(\d+)$`)
 )
@@ -19,150 +20,293 @@ type Finder interface {
 	Find(string) (string, bool)
 }
 
+// Match is the location of a match in the input. Start and End are byte
+// offsets in the input, therefore the matched text is input[Start:End]. Finder
+// is the finder that has found the match, and Style is the style it would
+// paint the match with.
+type Match struct {
+	Finder Finder
+	Style  Style
+	Start  int
+	End    int
+}
+
+// Indexer is a Finder that can report where its matches are in the input. It
+// returns all non-overlapping matches, or nil if there is none. Blush uses the
+// matches of all Indexers to paint the line once, therefore their matches never
+// collide with each other's escape sequences.
+type Indexer interface {
+	Finder
+	FindIndex(string) []Match
+}
+
+// Mode decides whether a search is a regexp or a literal string.
+type Mode int
+
+const (
+	// ModeAuto treats the search as a regexp if it contains any of the special
+	// characters of regular expressions, otherwise as a literal string.
+	ModeAuto Mode = iota
+	// ModeFixed always treats the search as a literal string.
+	ModeFixed
+	// ModeRegexp always treats the search as a regexp.
+	ModeRegexp
+)
+
 // NewLocator returns a Rx object if search is a valid regexp, otherwise it
 // returns Exact or Iexact. If insensitive is true, the match will be case
 // insensitive. The colour argument can be in short form (b) or long form
 // (blue). If it cannot find the colour, it will fall-back to DefaultColour. The
 // colour also can be in hex format, which should be started with a pound sign
-// (#666).
+// (#666). Text attributes can follow the colour, separated by commas or plus
+// signs in their long or short forms (red,bold,underline or r+b+u).
 func NewLocator(colour, search string, insensitive bool) Finder {
-	c := colorFromArg(colour)
-	if !isRegExp.MatchString(search) {
+	c := styleFromArg(colour)
+	if l, err := newLocator(ModeAuto, c, search, insensitive); err == nil {
+		return l
+	}
+	if insensitive {
+		return NewIexact(search, c)
+	}
+	return NewExact(search, c)
+}
+
+// NewLocatorE is like NewLocator, but it returns an error instead of falling
+// back. The error wraps ErrInvalidColour or ErrInvalidAttribute if the colour
+// can't be parsed, and ErrInvalidPattern if the search looks like a regexp but
+// doesn't compile.
+func NewLocatorE(colour, search string, insensitive bool) (Finder, error) {
+	return NewLocatorMode(ModeAuto, colour, search, insensitive)
+}
+
+// NewLocatorMode is like NewLocatorE, but the mode decides whether the search
+// is a regexp or a literal string. With ModeFixed it always returns an Exact or
+// an Iexact, and with ModeRegexp it always returns an Rx.
+func NewLocatorMode(mode Mode, colour, search string, insensitive bool) (Finder, error) {
+	c, err := ParseStyle(colour)
+	if err != nil {
+		return nil, err
+	}
+	return newLocator(mode, c, search, insensitive)
+}
+
+// newLocator returns an Rx if the search is a regexp in the given mode,
+// otherwise an Exact or an Iexact. It returns an error if the regexp doesn't
+// compile.
+func newLocator(mode Mode, s Style, search string, insensitive bool) (Finder, error) {
+	if mode == ModeFixed || (mode == ModeAuto && !isRegExp.MatchString(search)) {
 		if insensitive {
-			return NewIexact(search, c)
+			return NewIexact(search, s), nil
 		}
-		return NewExact(search, c)
+		return NewExact(search, s), nil
 	}
 
 	decore := fmt.Sprintf("(%s)", search)
 	if insensitive {
 		decore = fmt.Sprintf("(?i)%s", decore)
-		if o, err := regexp.Compile(decore); err == nil {
-			return NewRx(o, c)
-		}
-		return NewIexact(search, c)
 	}
-
-	if o, err := regexp.Compile(decore); err == nil {
-		return NewRx(o, c)
+	o, err := regexp.Compile(decore)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q: %s", ErrInvalidPattern, search, err)
 	}
-	return NewExact(search, c)
+	return NewRx(o, s), nil
 }
 
 // Exact looks for the exact word in the string.
 type Exact struct {
-	s      string
-	colour Colour
+	s     string
+	style Style
 }
 
-// NewExact returns a new instance of the Exact.
-func NewExact(s string, c Colour) Exact {
+// NewExact returns a new instance of the Exact. The style can be a Colour or
+// a Style.
+func NewExact(s string, c Styler) Exact {
 	return Exact{
-		s:      s,
-		colour: c,
+		s:     s,
+		style: c.Style(),
 	}
 }
 
 // Find looks for the exact string. Any strings it finds will be decorated with
-// the given Colour.
+// the given Style.
 func (e Exact) Find(input string) (string, bool) {
-	if strings.Contains(input, e.s) {
-		return e.colourise(input, e.colour), true
+	matches := e.FindIndex(input)
+	if len(matches) == 0 {
+		return "", false
 	}
-	return "", false
+	return render(input, matches, Stylise), true
 }
 
-func (e Exact) colourise(input string, c Colour) string {
-	if c == NoColour {
-		return input
-	}
-	return strings.ReplaceAll(input, e.s, Colourise(e.s, c))
+// FindIndex returns the location of all instances of the string in the input.
+func (e Exact) FindIndex(input string) []Match {
+	return indexAll(input, e.s, e.style, e)
 }
 
 // Colour returns the Colour property.
 func (e Exact) Colour() Colour {
-	return e.colour
+	return e.style.Colour
+}
+
+// Style returns the Style property.
+func (e Exact) Style() Style {
+	return e.style
 }
 
 // String will returned the colourised contents.
 func (e Exact) String() string {
-	return e.colourise(e.s, e.colour)
+	return Stylise(e.s, e.style)
 }
 
 // Iexact is like Exact but case insensitive.
 type Iexact struct {
-	s      string
-	colour Colour
+	s     string
+	style Style
 }
 
-// NewIexact returns a new instance of the Iexact.
-func NewIexact(s string, c Colour) Iexact {
+// NewIexact returns a new instance of the Iexact. The style can be a Colour
+// or a Style.
+func NewIexact(s string, c Styler) Iexact {
 	return Iexact{
-		s:      s,
-		colour: c,
+		s:     s,
+		style: c.Style(),
 	}
 }
 
 // Find looks for the exact string. Any strings it finds will be decorated with
-// the given Colour.
+// the given Style.
 func (i Iexact) Find(input string) (string, bool) {
-	if strings.Contains(strings.ToLower(input), strings.ToLower(i.s)) {
-		return i.colourise(input, i.colour), true
+	matches := i.FindIndex(input)
+	if len(matches) == 0 {
+		return "", false
 	}
-	return "", false
+	return render(input, matches, Stylise), true
 }
 
-func (i Iexact) colourise(input string, c Colour) string {
-	if c == NoColour {
-		return input
-	}
-	index := strings.Index(strings.ToLower(input), strings.ToLower(i.s))
-	end := len(i.s) + index
-	match := input[index:end]
-	return strings.ReplaceAll(input, match, Colourise(match, c))
+// FindIndex returns the location of all instances of the string in the input.
+func (i Iexact) FindIndex(input string) []Match {
+	return foldIndexAll(input, i.s, i.style, i)
 }
 
 // Colour returns the Colour property.
 func (i Iexact) Colour() Colour {
-	return i.colour
+	return i.style.Colour
+}
+
+// Style returns the Style property.
+func (i Iexact) Style() Style {
+	return i.style
 }
 
 // String will returned the colourised contents.
 func (i Iexact) String() string {
-	return i.colourise(i.s, i.colour)
+	return Stylise(i.s, i.style)
 }
 
 // Rx is the regexp implementation of the Locator.
 type Rx struct {
 	*regexp.Regexp
-	colour Colour
+	style Style
 }
 
-// NewRx returns a new instance of the Rx.
-func NewRx(r *regexp.Regexp, c Colour) Rx {
+// NewRx returns a new instance of the Rx. The style can be a Colour or a
+// Style.
+func NewRx(r *regexp.Regexp, c Styler) Rx {
 	return Rx{
 		Regexp: r,
-		colour: c,
+		style:  c.Style(),
 	}
 }
 
 // Find looks for the string matching `r` regular expression. Any strings it
-// finds will be decorated with the given Colour.
+// finds will be decorated with the given Style.
 func (r Rx) Find(input string) (string, bool) {
-	if r.MatchString(input) {
-		return r.colourise(input, r.colour), true
+	matches := r.FindIndex(input)
+	if len(matches) == 0 {
+		return "", false
 	}
-	return "", false
+	return render(input, matches, Stylise), true
 }
 
-func (r Rx) colourise(input string, c Colour) string {
-	if c == NoColour {
-		return input
+// FindIndex returns the location of all matches of the regular expression in
+// the input. Note that it shadows the FindIndex method of the embedded Regexp;
+// use r.Regexp.FindIndex if you need that one.
+func (r Rx) FindIndex(input string) []Match {
+	loc := r.FindAllStringIndex(input, -1)
+	if loc == nil {
+		return nil
+	}
+	matches := make([]Match, len(loc))
+	for i, l := range loc {
+		matches[i] = Match{Finder: r, Style: r.style, Start: l[0], End: l[1]}
 	}
-	return r.ReplaceAllString(input, Colourise("$1", c))
+	return matches
 }
 
 // Colour returns the Colour property.
 func (r Rx) Colour() Colour {
-	return r.colour
+	return r.style.Colour
+}
+
+// Style returns the Style property.
+func (r Rx) Style() Style {
+	return r.style
+}
+
+// indexAll returns the location of all non-overlapping instances of s in input.
+// An empty s matches the beginning of the input.
+func indexAll(input, s string, st Style, f Finder) []Match {
+	if s == "" {
+		return []Match{{Finder: f, Style: st}}
+	}
+	var matches []Match
+	for offset := 0; offset <= len(input); {
+		i := strings.Index(input[offset:], s)
+		if i < 0 {
+			break
+		}
+		start := offset + i
+		offset = start + len(s)
+		matches = append(matches, Match{Finder: f, Style: st, Start: start, End: offset})
+	}
+	return matches
+}
+
+// foldIndexAll is like indexAll, but matches s case insensitively. The matches
+// are reported against the input, even if the upper and lower case forms of a
+// rune have different lengths.
+func foldIndexAll(input, s string, st Style, f Finder) []Match {
+	if isASCII(input) && isASCII(s) {
+		return indexAll(strings.ToLower(input), strings.ToLower(s), st, f)
+	}
+	if s == "" {
+		return []Match{{Finder: f, Style: st}}
+	}
+	var (
+		matches []Match
+		runes   = utf8.RuneCountInString(s)
+	)
+	for start := 0; start < len(input); {
+		end := start
+		for n := 0; n < runes && end < len(input); n++ {
+			_, size := utf8.DecodeRuneInString(input[end:])
+			end += size
+		}
+		if strings.EqualFold(input[start:end], s) {
+			matches = append(matches, Match{Finder: f, Style: st, Start: start, End: end})
+			start = end
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(input[start:])
+		start += size
+	}
+	return matches
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
 }
@@ -1,6 +1,7 @@
 package blush_test
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
 	"testing"
@@ -54,6 +55,9 @@ func TestNewLocatorColours(t *testing.T) {
 		{"hash ababAB", "#ababAB", blush.Colour{Foreground: blush.RGB{R: 171, G: 171, B: 171}, Background: blush.NoRGB}},
 		{"hash hhhhhh", "#hhhhhh", blush.DefaultColour},
 		{"hash aaaaaaa", "#aaaaaaa", blush.DefaultColour},
+		{"background", "yl/bl", blush.Colour{Foreground: blush.FgYellow, Background: blush.FgBlack}},
+		{"background only", "/#fff", blush.Colour{Foreground: blush.NoRGB, Background: blush.FgWhite}},
+		{"bad background", "yl/blk", blush.DefaultColour},
 	}
 	for _, tc := range tcs {
 		tc := tc
@@ -95,6 +99,8 @@ func TestNewLocatorRx(t *testing.T) {
 		{"with star", "blah blah.*", []string{"blah blah", "aa blah blah aa"}},
 		{"with curly brackets", "a{3}", []string{"aaa", "aa aaa aa"}},
 		{"with brackets", "[ab]", []string{"kjhadf", "kjlrbrlkj", "sdbsdha"}},
+		{"with pipe", "aaa|bbb", []string{"aaa", "sss bbb"}},
+		{"with parentheses", "(aaa)", []string{"aaa", "sss aaa"}},
 	}
 
 	for _, tc := range tcs {
@@ -108,6 +114,97 @@ func TestNewLocatorRx(t *testing.T) {
 	}
 }
 
+// nolint:misspell // it's ok.
+func TestNewLocatorE(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name        string
+		colour      string
+		search      string
+		insensitive bool
+		wantType    blush.Finder
+		wantErr     error
+	}{
+		{"exact", "r", "aaa", false, blush.Exact{}, nil},
+		{"iexact", "r", "aaa", true, blush.Iexact{}, nil},
+		{"rx", "r", "^aaa", false, blush.Rx{}, nil},
+		{"insensitive rx", "r", "^aaa", true, blush.Rx{}, nil},
+		{"default colour", "", "aaa", false, blush.Exact{}, nil},
+		{"bad colour", "gren", "aaa", false, nil, blush.ErrInvalidColour},
+		{"bad hex", "#ggg", "aaa", false, nil, blush.ErrInvalidColour},
+		{"bad attribute", "r,blinking", "aaa", false, nil, blush.ErrInvalidAttribute},
+		{"bad rx", "r", "a(.b", false, nil, blush.ErrInvalidPattern},
+		{"bad insensitive rx", "r", "[a", true, nil, blush.ErrInvalidPattern},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			l, err := blush.NewLocatorE(tc.colour, tc.search, tc.insensitive)
+			if tc.wantErr != nil {
+				assert.True(t, errors.Is(err, tc.wantErr))
+				assert.Nil(t, l)
+				return
+			}
+			assert.NoError(t, err)
+			assert.IsType(t, tc.wantType, l)
+		})
+	}
+
+	_, err := blush.NewLocatorE("r", "a(.b", false)
+	assert.Contains(t, err.Error(), `"a(.b"`)
+
+	l := blush.NewLocator("gren", "a(.b", false)
+	assert.IsType(t, blush.Exact{}, l)
+	assert.Equal(t, blush.DefaultColour, l.(colourer).Colour())
+}
+
+func TestNewLocatorMode(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name        string
+		mode        blush.Mode
+		search      string
+		insensitive bool
+		wantType    blush.Finder
+	}{
+		{"auto literal", blush.ModeAuto, "aaa", false, blush.Exact{}},
+		{"auto regexp", blush.ModeAuto, "a.a", false, blush.Rx{}},
+		{"auto backslash", blush.ModeAuto, `C:\path`, false, blush.Exact{}},
+		{"auto alternation", blush.ModeAuto, "foo|bar", false, blush.Rx{}},
+		{"fixed", blush.ModeFixed, "1.5", false, blush.Exact{}},
+		{"fixed brackets", blush.ModeFixed, "[WARN]", false, blush.Exact{}},
+		{"fixed invalid regexp", blush.ModeFixed, "a(.b", false, blush.Exact{}},
+		{"fixed insensitive", blush.ModeFixed, "1.5", true, blush.Iexact{}},
+		{"regexp", blush.ModeRegexp, "aaa", false, blush.Rx{}},
+		{"regexp insensitive", blush.ModeRegexp, "aaa", true, blush.Rx{}},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			l, err := blush.NewLocatorMode(tc.mode, "r", tc.search, tc.insensitive)
+			assert.NoError(t, err)
+			assert.IsType(t, tc.wantType, l)
+		})
+	}
+
+	l, err := blush.NewLocatorMode(blush.ModeFixed, "r", "1.5", false)
+	assert.NoError(t, err)
+	got, ok := l.Find("version 105 and 1.5")
+	assert.True(t, ok)
+	assert.Equal(t, "version 105 and "+blush.Colourise("1.5", blush.Red), got)
+
+	l, err = blush.NewLocatorMode(blush.ModeRegexp, "r", "aaa", true)
+	assert.NoError(t, err)
+	got, ok = l.Find("AAA")
+	assert.True(t, ok)
+	assert.Equal(t, blush.Colourise("AAA", blush.Red), got)
+
+	_, err = blush.NewLocatorMode(blush.ModeRegexp, "r", "a(b", false)
+	assert.True(t, errors.Is(err, blush.ErrInvalidPattern))
+	_, err = blush.NewLocatorMode(blush.ModeFixed, "gren", "aaa", false)
+	assert.True(t, errors.Is(err, blush.ErrInvalidColour))
+}
+
 func TestNewLocatorRxColours(t *testing.T) {
 	t.Parallel()
 	rx := blush.NewLocator("b", "a{3}", false)
@@ -186,6 +283,9 @@ func TestRxFind(t *testing.T) {
 		{"some parts not matched", "(Aaa)", blush.NoColour, "bb aaa bb", "", false},
 		{"exact blue", "(aaa)", blush.Blue, "aaa", blush.Colourise("aaa", blush.Blue), true},
 		{"some parts blue", "(aaa)", blush.Blue, "bb aaa bb", "bb " + blush.Colourise("aaa", blush.Blue) + " bb", true},
+		{"no group", "a+", blush.Blue, "bb aaa bb", "bb " + blush.Colourise("aaa", blush.Blue) + " bb", true},
+		{"inner group", "b(a+)b", blush.Blue, "bab", blush.Colourise("bab", blush.Blue), true},
+		{"empty match", "^$", blush.Blue, "", "", true},
 	}
 	for _, tc := range tcs {
 		tc := tc
@@ -226,6 +326,12 @@ func TestIexact(t *testing.T) {
 		{"i exact blue", "AAA", blush.Blue, "aaa", blush.Colourise("aaa", blush.Blue), true},
 		{"some parts blue", "aaa", blush.Blue, "bb aaa bb", "bb " + blush.Colourise("aaa", blush.Blue) + " bb", true},
 		{"i some parts blue", "AAA", blush.Blue, "bb aaa bb", "bb " + blush.Colourise("aaa", blush.Blue) + " bb", true},
+		{
+			"mixed cases", "aaa", blush.Blue, "aaa AaA",
+			blush.Colourise("aaa", blush.Blue) + " " + blush.Colourise("AaA", blush.Blue), true,
+		},
+		{"unicode", "ÇA", blush.Blue, "ça va", blush.Colourise("ça", blush.Blue) + " va", true},
+		{"kelvin", "k", blush.Blue, "\u212a", blush.Colourise("\u212a", blush.Blue), true},
 	}
 	for _, tc := range tcs {
 		tc := tc
@@ -305,3 +411,80 @@ func TestColourNewGroup(t *testing.T) {
 	assert.NotEqual(t, c1, e.Colour())
 	assert.NotEqual(t, c1.Background, e.Colour().Background)
 }
+
+func TestFindIndex(t *testing.T) {
+	t.Parallel()
+	exact := blush.NewExact("aa", blush.Blue)
+	iexact := blush.NewIexact("aa", blush.Red)
+	rx := blush.NewRx(regexp.MustCompile("a+"), blush.Green)
+	tcs := []struct {
+		name   string
+		finder blush.Indexer
+		input  string
+		want   []blush.Match
+	}{
+		{"exact not found", exact, "bbb", nil},
+		{"exact", exact, "b aa b", []blush.Match{
+			{Finder: exact, Style: blush.Blue.Style(), Start: 2, End: 4},
+		}},
+		{"exact non overlapping", exact, "aaaaa", []blush.Match{
+			{Finder: exact, Style: blush.Blue.Style(), Start: 0, End: 2},
+			{Finder: exact, Style: blush.Blue.Style(), Start: 2, End: 4},
+		}},
+		{"exact empty", blush.NewExact("", blush.Blue), "aaa", []blush.Match{
+			{Finder: blush.NewExact("", blush.Blue), Style: blush.Blue.Style()},
+		}},
+		{"iexact not found", iexact, "bbb", nil},
+		{"iexact", iexact, "Aa b aA", []blush.Match{
+			{Finder: iexact, Style: blush.Red.Style(), Start: 0, End: 2},
+			{Finder: iexact, Style: blush.Red.Style(), Start: 5, End: 7},
+		}},
+		{"iexact unicode", iexact, "ça AA", []blush.Match{
+			{Finder: iexact, Style: blush.Red.Style(), Start: 4, End: 6},
+		}},
+		{"rx not found", rx, "bbb", nil},
+		{"rx", rx, "a b aaa", []blush.Match{
+			{Finder: rx, Style: blush.Green.Style(), Start: 0, End: 1},
+			{Finder: rx, Style: blush.Green.Style(), Start: 4, End: 7},
+		}},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.finder.FindIndex(tc.input)
+			assert.Equal(t, tc.want, got)
+			for _, m := range got {
+				assert.True(t, m.Start <= m.End)
+				assert.True(t, m.End <= len(tc.input))
+			}
+		})
+	}
+}
+
+func TestFindIndexLocator(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name        string
+		search      string
+		insensitive bool
+	}{
+		{"exact", "aaa", false},
+		{"iexact", "aaa", true},
+		{"rx", "a{3}", false},
+		{"irx", "A{3}", true},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			l := blush.NewLocator("b", tc.search, tc.insensitive)
+			ix, ok := l.(blush.Indexer)
+			assert.True(t, ok)
+			got := ix.FindIndex("bb aaa bb")
+			assert.Len(t, got, 1)
+			assert.Equal(t, 3, got[0].Start)
+			assert.Equal(t, 6, got[0].End)
+			assert.Equal(t, blush.Blue, got[0].Style.Colour)
+			assert.Equal(t, l, got[0].Finder)
+		})
+	}
+}
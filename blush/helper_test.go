@@ -1,6 +1,9 @@
 package blush_test
 
-import "io"
+import (
+	"io"
+	"strings"
+)
 
 // this file contains helpers for all tests in this package.
 
@@ -22,3 +25,44 @@ type badWriter struct {
 }
 
 func (b *badWriter) Write(p []byte) (int, error) { return b.writeFunc(p) }
+
+// finderMock is a Finder that is not an Indexer.
+type finderMock struct {
+	find func(string) (string, bool)
+}
+
+func (f *finderMock) Find(s string) (string, bool) { return f.find(s) }
+
+// badReader returns the err after it returns the contents of r.
+type badReader struct {
+	r   io.Reader
+	err error
+}
+
+func (b *badReader) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	if err == io.EOF {
+		return n, b.err
+	}
+	return n, err
+}
+
+// followReader returns the sources in order, as if the sources with the same
+// names were appended to the previous ones.
+type followReader struct {
+	sources [][2]string // name and contents.
+}
+
+func (f *followReader) NextSource() (string, io.Reader, error) {
+	if len(f.sources) == 0 {
+		return "", nil, io.EOF
+	}
+	s := f.sources[0]
+	f.sources = f.sources[1:]
+	return s[0], strings.NewReader(s[1]), nil
+}
+
+func (f *followReader) CloseSource() error       { return nil }
+func (f *followReader) Read([]byte) (int, error) { return 0, io.EOF }
+func (f *followReader) Close() error             { return nil }
+func (f *followReader) Following() bool          { return true }
@@ -0,0 +1,224 @@
+package blush
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+)
+
+// DefaultRenderer is used when no Renderer is set on Blush.
+var DefaultRenderer Renderer = ANSIRenderer{}
+
+// Renderer turns a line and the matches found in it into the output. The
+// matches are reported against the line and can overlap; Renderers should
+// paint the overlapping parts with the style of the match that comes later.
+type Renderer interface {
+	Render(line string, matches []Match) string
+}
+
+// NewRenderer returns an ANSI Renderer for the given colour depth. It returns
+// the DefaultRenderer if the depth is unknown.
+func NewRenderer(depth ColourDepth) Renderer {
+	switch depth {
+	case Depth16:
+		return ANSI16Renderer{}
+	case Depth256:
+		return ANSIRenderer{}
+	case DepthTrueColour:
+		return TrueColourRenderer{}
+	}
+	return DefaultRenderer
+}
+
+// ANSI16Renderer paints the matches with the basic 16 colours of the terminal.
+// The colours are converted to the closest one in the palette.
+type ANSI16Renderer struct{}
+
+// Render paints the matches on the line.
+func (ANSI16Renderer) Render(line string, matches []Match) string {
+	return render(line, matches, stylise16)
+}
+
+// ANSIRenderer paints the matches with 256 colour ANSI escape sequences. The
+// colours are converted to their nearest value in the 6x6x6 colour cube.
+type ANSIRenderer struct{}
+
+// Render paints the matches on the line.
+func (ANSIRenderer) Render(line string, matches []Match) string {
+	return render(line, matches, Stylise)
+}
+
+// TrueColourRenderer paints the matches with 24-bit ANSI escape sequences,
+// therefore the colours are shown as they are on terminals that support them.
+type TrueColourRenderer struct{}
+
+// Render paints the matches on the line.
+func (TrueColourRenderer) Render(line string, matches []Match) string {
+	return render(line, matches, styliseTrue)
+}
+
+// HTMLPrefix is the prefix of the CSS classes HTMLRenderer uses.
+const HTMLPrefix = "blush-"
+
+// HTMLRenderer escapes the line and wraps the matches in span elements. If
+// Classes is false, the styles are set inline. Otherwise each span gets a class
+// for its foreground and background colours and its attributes, for example
+// "blush-fg-ff0000 blush-bg-460000 blush-bold", and you should provide the
+// styles. The output should be put in a pre element to keep the spaces and the
+// newlines.
+type HTMLRenderer struct {
+	Classes bool
+}
+
+// Render escapes the line and paints the matches on it.
+func (h HTMLRenderer) Render(line string, matches []Match) string {
+	return render(line, matches, h.wrap)
+}
+
+// htmlAttributes holds the CSS declarations of the attributes. Reverse swaps
+// the colours, and underline and strikethrough share the text-decoration
+// property, therefore they are handled separately.
+var htmlAttributes = []struct {
+	attr Attribute
+	decl string
+}{
+	{Bold, "font-weight: bold"},
+	{Dim, "opacity: 0.5"},
+	{Italic, "font-style: italic"},
+}
+
+func (h HTMLRenderer) wrap(input string, s Style) string {
+	input = html.EscapeString(input)
+	if s == NoStyle {
+		return input
+	}
+	if h.Classes {
+		return fmt.Sprintf(`<span class="%s">%s</span>`, strings.Join(h.classes(s), " "), input)
+	}
+	return fmt.Sprintf(`<span style="%s">%s</span>`, strings.Join(h.declarations(s), "; "), input)
+}
+
+func (h HTMLRenderer) classes(s Style) []string {
+	var classes []string
+	if s.Foreground != NoRGB {
+		classes = append(classes, HTMLPrefix+"fg-"+hex(s.Foreground))
+	}
+	if s.Background != NoRGB {
+		classes = append(classes, HTMLPrefix+"bg-"+hex(s.Background))
+	}
+	for _, at := range attributes {
+		if s.Attributes&at.attr != 0 {
+			classes = append(classes, HTMLPrefix+at.name)
+		}
+	}
+	return classes
+}
+
+func (h HTMLRenderer) declarations(s Style) []string {
+	var (
+		decls  []string
+		fg, bg = s.Foreground, s.Background
+	)
+	if s.Attributes&Reverse != 0 {
+		fg, bg = bg, fg
+	}
+	if fg != NoRGB {
+		decls = append(decls, "color: #"+hex(fg))
+	}
+	if bg != NoRGB {
+		decls = append(decls, "background-color: #"+hex(bg))
+	}
+	for _, at := range htmlAttributes {
+		if s.Attributes&at.attr != 0 {
+			decls = append(decls, at.decl)
+		}
+	}
+	var lines []string
+	if s.Attributes&Underline != 0 {
+		lines = append(lines, "underline")
+	}
+	if s.Attributes&Strikethrough != 0 {
+		lines = append(lines, "line-through")
+	}
+	if len(lines) > 0 {
+		decls = append(decls, "text-decoration: "+strings.Join(lines, " "))
+	}
+	return decls
+}
+
+// PlainRenderer returns the line as it is.
+type PlainRenderer struct{}
+
+// Render returns the line without any decorations.
+func (PlainRenderer) Render(line string, _ []Match) string { return line }
+
+// segment is a part of the line that is painted with a single style.
+type segment struct {
+	start int
+	end   int
+	style Style
+}
+
+// render paints the matches on the line by passing each segment of the line to
+// the wrap function. The unmatched parts are passed with the NoStyle value.
+// The matches are all reported against the original line, therefore no finder
+// can ever see the escape sequences of another one. When matches overlap, the
+// one that comes later wins the overlapping part, so there are no nested
+// escape sequences and each coloured part of the line is closed exactly once.
+func render(line string, matches []Match, wrap func(string, Style) string) string {
+	segments := paint(len(line), matches)
+	var sb strings.Builder
+	sb.Grow(len(line))
+	for _, s := range segments {
+		sb.WriteString(wrap(line[s.start:s.end], s.style))
+	}
+	return sb.String()
+}
+
+// paint splits a line of the given length into segments. The unmatched parts
+// of the line are returned with the NoStyle value. Matches with NoStyle do not
+// paint anything, so they never hide the style of other matches.
+func paint(length int, matches []Match) []segment {
+	bounds := make([]int, 0, len(matches)*2+2)
+	bounds = append(bounds, 0, length)
+	for _, m := range matches {
+		bounds = append(bounds, clamp(m.Start, length), clamp(m.End, length))
+	}
+	sort.Ints(bounds)
+
+	segments := make([]segment, 0, len(bounds))
+	for i := 1; i < len(bounds); i++ {
+		start, end := bounds[i-1], bounds[i]
+		if start == end {
+			continue
+		}
+		st := NoStyle
+		for j := len(matches) - 1; j >= 0; j-- {
+			m := matches[j]
+			if m.Style != NoStyle && m.Start <= start && m.End >= end {
+				st = m.Style
+				break
+			}
+		}
+		if n := len(segments); n > 0 && segments[n-1].style == st {
+			segments[n-1].end = end
+			continue
+		}
+		segments = append(segments, segment{start: start, end: end, style: st})
+	}
+	if len(segments) == 0 {
+		segments = append(segments, segment{style: NoStyle})
+	}
+	return segments
+}
+
+func clamp(n, length int) int {
+	if n < 0 {
+		return 0
+	}
+	if n > length {
+		return length
+	}
+	return n
+}
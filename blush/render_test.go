@@ -0,0 +1,109 @@
+package blush_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/alecthomas/assert"
+	"github.com/arsham/blush/blush"
+)
+
+func TestRenderers(t *testing.T) {
+	t.Parallel()
+	line := "a <b> & c\n"
+	red := blush.NewExact("<b>", blush.Red)
+	bg := blush.Colour{Foreground: blush.NoRGB, Background: blush.BgBlue}
+	both := blush.Colour{Foreground: blush.FgYellow, Background: blush.BgRed}
+	tcs := []struct {
+		name     string
+		renderer blush.Renderer
+		matches  []blush.Match
+		want     string
+	}{
+		{"ansi no match", blush.ANSIRenderer{}, nil, line},
+		{
+			"ansi", blush.ANSIRenderer{},
+			[]blush.Match{{Finder: red, Style: blush.Red.Style(), Start: 2, End: 5}},
+			"a " + blush.Colourise("<b>", blush.Red) + " & c\n",
+		},
+		{"true colour no match", blush.TrueColourRenderer{}, nil, line},
+		{
+			"true colour", blush.TrueColourRenderer{},
+			[]blush.Match{{Style: blush.Colour{Foreground: blush.RGB{R: 1, G: 2, B: 3}, Background: blush.NoRGB}.Style(), Start: 2, End: 5}},
+			"a \033[38;2;1;2;3m<b>\033[0m & c\n",
+		},
+		{
+			"true colour both", blush.TrueColourRenderer{},
+			[]blush.Match{{Style: both.Style(), Start: 0, End: 1}},
+			"\033[38;2;255;255;0m\033[48;2;70;0;0ma\033[0m <b> & c\n",
+		},
+		{"html no match", blush.HTMLRenderer{}, nil, "a &lt;b&gt; &amp; c\n"},
+		{
+			"html inline", blush.HTMLRenderer{},
+			[]blush.Match{{Style: blush.Red.Style(), Start: 2, End: 5}},
+			`a <span style="color: #ff0000">&lt;b&gt;</span> &amp; c` + "\n",
+		},
+		{
+			"html inline both", blush.HTMLRenderer{},
+			[]blush.Match{{Style: both.Style(), Start: 0, End: 1}},
+			`<span style="color: #ffff00; background-color: #460000">a</span> &lt;b&gt; &amp; c` + "\n",
+		},
+		{
+			"html classes", blush.HTMLRenderer{Classes: true},
+			[]blush.Match{{Style: blush.Red.Style(), Start: 2, End: 5}},
+			`a <span class="blush-fg-ff0000">&lt;b&gt;</span> &amp; c` + "\n",
+		},
+		{
+			"html classes background", blush.HTMLRenderer{Classes: true},
+			[]blush.Match{{Style: bg.Style(), Start: 8, End: 9}},
+			`a &lt;b&gt; &amp; <span class="blush-bg-000046">c</span>` + "\n",
+		},
+		{
+			"html overlapping", blush.HTMLRenderer{Classes: true},
+			[]blush.Match{
+				{Style: blush.Red.Style(), Start: 0, End: 5},
+				{Style: blush.Blue.Style(), Start: 2, End: 3},
+			},
+			`<span class="blush-fg-ff0000">a </span><span class="blush-fg-0000ff">&lt;</span>` +
+				`<span class="blush-fg-ff0000">b&gt;</span> &amp; c` + "\n",
+		},
+		{"plain", blush.PlainRenderer{}, []blush.Match{{Style: blush.Red.Style(), Start: 2, End: 5}}, line},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.renderer.Render(line, tc.matches)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestBlushRenderer(t *testing.T) {
+	t.Parallel()
+	input := "one <two> three\nfour\n"
+	tcs := []struct {
+		name     string
+		renderer blush.Renderer
+		want     string
+	}{
+		{"default", nil, "one <" + blush.Colourise("two", blush.Red) + "> three\nfour\n"},
+		{"ansi", blush.ANSIRenderer{}, "one <" + blush.Colourise("two", blush.Red) + "> three\nfour\n"},
+		{"plain", blush.PlainRenderer{}, input},
+		{"html", blush.HTMLRenderer{}, `one &lt;<span style="color: #ff0000">two</span>&gt; three` + "\nfour\n"},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			b := &blush.Blush{
+				Reader:   io.NopCloser(bytes.NewBufferString(input)),
+				Finders:  []blush.Finder{blush.NewExact("two", blush.Red)},
+				Renderer: tc.renderer,
+			}
+			buf := &bytes.Buffer{}
+			_, err := b.WriteTo(buf)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+}
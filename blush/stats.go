@@ -0,0 +1,41 @@
+package blush
+
+import "time"
+
+// Stats holds the statistics of the input Blush has read. Files is the number
+// of sources, which is 1 if the Reader doesn't read from files. Matched is the
+// number of the selected lines, which are the lines the finders match, or the
+// lines they don't match if Invert is set. Listed is the number of the sources
+// that are listed with FilesWithMatches or FilesWithoutMatch.
+type Stats struct {
+	Finders []FinderStats
+	Matched int
+	Listed  int
+	Files   int
+	Lines   int
+	Bytes   int64
+	Elapsed time.Duration
+}
+
+// FinderStats holds the number of matches of a finder and the number of the
+// lines it has matched. If the finder is not an Indexer, each matched line is
+// counted as one match.
+type FinderStats struct {
+	Finder  Finder
+	Matches int
+	Lines   int
+}
+
+// Stats returns the statistics of the input. It should be called after
+// WriteTo returns, or Read returns io.EOF.
+func (b *Blush) Stats() Stats {
+	s := b.stats
+	s.Finders = append([]FinderStats(nil), b.stats.Finders...)
+	return s
+}
+
+// found records n matches of the finder at index i in a line.
+func (b *Blush) found(i, n int) {
+	b.stats.Finders[i].Matches += n
+	b.stats.Finders[i].Lines++
+}
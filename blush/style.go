@@ -0,0 +1,161 @@
+package blush
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Attribute is a text attribute, like bold or underline. Attributes can be
+// combined with the | operator.
+type Attribute uint8
+
+// These are the text attributes that can be set on a Style. Please note that
+// not all terminals support all of them.
+const (
+	Bold Attribute = 1 << iota
+	Dim
+	Italic
+	Underline
+	Reverse
+	Strikethrough
+)
+
+// attributes holds the SGR codes and the names of the attributes. The short
+// names can only be used after a colour, for example "r+b" is red and bold.
+var attributes = []struct {
+	attr  Attribute
+	code  int
+	name  string
+	short string
+}{
+	{Bold, 1, "bold", "b"},
+	{Dim, 2, "dim", "d"},
+	{Italic, 3, "italic", "i"},
+	{Underline, 4, "underline", "u"},
+	{Reverse, 7, "reverse", "r"},
+	{Strikethrough, 9, "strikethrough", "s"},
+}
+
+// Style is a Colour with text attributes.
+type Style struct {
+	Colour
+	Attributes Attribute
+}
+
+// NoStyle does not decorate the text at all.
+var NoStyle = Style{Colour: NoColour}
+
+// Styler is implemented by Colour and Style. Finders can be created with
+// either of them.
+type Styler interface {
+	Style() Style
+}
+
+// Style returns the colour as a Style without any attributes.
+func (c Colour) Style() Style {
+	return Style{Colour: c}
+}
+
+// Style returns the style itself.
+func (s Style) Style() Style {
+	return s
+}
+
+// Stylise wraps the input between the escape sequences of the style. The
+// colours are shown in 256 colours.
+func Stylise(input string, s Style) string {
+	return stylise(input, s, foreground, background)
+}
+
+// stylise wraps the input between the attributes and the colours of the
+// style, which are turned into escape sequences with fg and bg.
+func stylise(input string, s Style, fg, bg func(RGB) string) string {
+	if s == NoStyle {
+		return input
+	}
+	var sb strings.Builder
+	sb.WriteString(sgrAttributes(s.Attributes))
+	if s.Foreground != NoRGB {
+		sb.WriteString(fg(s.Foreground))
+	}
+	if s.Background != NoRGB {
+		sb.WriteString(bg(s.Background))
+	}
+	sb.WriteString(input)
+	sb.WriteString(unformat())
+	return sb.String()
+}
+
+// sgrAttributes returns the escape sequence that turns the attributes on, or
+// an empty string if there is none.
+func sgrAttributes(a Attribute) string {
+	if a == 0 {
+		return ""
+	}
+	codes := make([]string, 0, len(attributes))
+	for _, at := range attributes {
+		if a&at.attr != 0 {
+			codes = append(codes, strconv.Itoa(at.code))
+		}
+	}
+	return "\033[" + strings.Join(codes, ";") + "m"
+}
+
+// ParseStyle parses a colour with optional text attributes, for example
+// "yl/bl,bold,underline" or "r+b". The colour is parsed with ParseColour. It
+// returns an error wrapping ErrInvalidColour or ErrInvalidAttribute if any
+// part of the spec is not valid.
+func ParseStyle(spec string) (Style, error) {
+	colour, attrs := splitStyle(spec)
+	c, err := ParseColour(colour)
+	if err != nil {
+		return NoStyle, err
+	}
+	s := c.Style()
+	for _, p := range attrs {
+		a, ok := attributeFromArg(p)
+		if !ok {
+			return NoStyle, fmt.Errorf("%w: %q in %q", ErrInvalidAttribute, p, spec)
+		}
+		s.Attributes |= a
+	}
+	return s, nil
+}
+
+// styleFromArg returns the style of a colour argument with optional attributes
+// that are separated by commas or plus signs, for example "red,bold,underline"
+// or "g+u". Any unknown attributes are ignored.
+func styleFromArg(arg string) Style {
+	colour, attrs := splitStyle(arg)
+	s := colorFromArg(colour).Style()
+	for _, p := range attrs {
+		if a, ok := attributeFromArg(p); ok {
+			s.Attributes |= a
+		}
+	}
+	return s
+}
+
+// splitStyle splits the spec at the first comma or plus sign into its colour
+// and its attributes.
+func splitStyle(spec string) (colour string, attrs []string) {
+	colour = spec
+	if i := strings.IndexAny(spec, ",+"); i >= 0 {
+		colour = spec[:i]
+		attrs = strings.FieldsFunc(spec[i+1:], func(r rune) bool {
+			return r == ',' || r == '+'
+		})
+	}
+	return colour, attrs
+}
+
+// attributeFromArg returns the attribute of the short or long name.
+func attributeFromArg(name string) (Attribute, bool) {
+	for _, at := range attributes {
+		if name == at.name || name == at.short {
+			return at.attr, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,183 @@
+package blush_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alecthomas/assert"
+	"github.com/arsham/blush/blush"
+)
+
+type styler interface {
+	Style() blush.Style
+}
+
+func TestStylise(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name  string
+		style blush.Style
+		want  string
+	}{
+		{"no style", blush.NoStyle, "aaa"},
+		{"colour", blush.Red.Style(), blush.Colourise("aaa", blush.Red)},
+		{"bold", blush.Style{Colour: blush.NoColour, Attributes: blush.Bold}, "\033[1maaa\033[0m"},
+		{
+			"all attributes",
+			blush.Style{
+				Colour:     blush.NoColour,
+				Attributes: blush.Bold | blush.Dim | blush.Italic | blush.Underline | blush.Reverse | blush.Strikethrough,
+			},
+			"\033[1;2;3;4;7;9maaa\033[0m",
+		},
+		{"colour and attributes", blush.Style{Colour: blush.Red, Attributes: blush.Underline | blush.Bold}, "\033[1;4m\033[38;5;196maaa\033[0m"},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := blush.Stylise("aaa", tc.style)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestColourStyle(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, blush.Style{Colour: blush.Red}, blush.Red.Style())
+	assert.Equal(t, blush.NoStyle, blush.NoColour.Style())
+	s := blush.Style{Colour: blush.Red, Attributes: blush.Bold}
+	assert.Equal(t, s, s.Style())
+}
+
+// nolint:misspell // it's ok.
+func TestNewLocatorStyles(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name   string
+		colour string
+		want   blush.Style
+	}{
+		{"colour only", "red", blush.Red.Style()},
+		{"bold", "red,bold", blush.Style{Colour: blush.Red, Attributes: blush.Bold}},
+		{"bold underline", "red,bold,underline", blush.Style{Colour: blush.Red, Attributes: blush.Bold | blush.Underline}},
+		{"short", "g+u", blush.Style{Colour: blush.Green, Attributes: blush.Underline}},
+		{"short many", "g+u+b+i", blush.Style{Colour: blush.Green, Attributes: blush.Underline | blush.Bold | blush.Italic}},
+		{"mixed", "b,u+dim", blush.Style{Colour: blush.Blue, Attributes: blush.Underline | blush.Dim}},
+		{"reverse strike", "yl+r+s", blush.Style{Colour: blush.Yellow, Attributes: blush.Reverse | blush.Strikethrough}},
+		{"hex", "#f00,bold", blush.Style{Colour: blush.Colour{Foreground: blush.FgRed, Background: blush.NoRGB}, Attributes: blush.Bold}},
+		{"no colour", "no-colour,bold", blush.Style{Colour: blush.NoColour, Attributes: blush.Bold}},
+		{"default colour", "+u", blush.Style{Colour: blush.DefaultColour, Attributes: blush.Underline}},
+		{"unknown attribute", "red,blinking", blush.Red.Style()},
+		{"repeated", "red,u,u", blush.Style{Colour: blush.Red, Attributes: blush.Underline}},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			l := blush.NewLocator(tc.colour, "aaa", false)
+			s, ok := l.(styler)
+			assert.True(t, ok)
+			assert.Equal(t, tc.want, s.Style())
+			c, ok := l.(colourer)
+			assert.True(t, ok)
+			assert.Equal(t, tc.want.Colour, c.Colour())
+		})
+	}
+
+	l := blush.NewLocator("b1,bold", "aaa", false)
+	s := l.(styler).Style()
+	assert.Equal(t, blush.FgBlue, s.Foreground)
+	assert.Equal(t, blush.BgBlue, s.Background)
+	assert.Equal(t, blush.Bold, s.Attributes)
+}
+
+func TestStyledRenderers(t *testing.T) {
+	t.Parallel()
+	style := blush.Style{Colour: blush.Red, Attributes: blush.Bold | blush.Underline}
+	reversed := blush.Style{Colour: blush.Colour{Foreground: blush.FgRed, Background: blush.BgBlue}, Attributes: blush.Reverse | blush.Strikethrough}
+	tcs := []struct {
+		name     string
+		renderer blush.Renderer
+		style    blush.Style
+		want     string
+	}{
+		{"ansi", blush.ANSIRenderer{}, style, "\033[1;4m\033[38;5;196maaa\033[0m"},
+		{"ansi16", blush.ANSI16Renderer{}, style, "\033[1;4m\033[91maaa\033[0m"},
+		{"true colour", blush.TrueColourRenderer{}, style, "\033[1;4m\033[38;2;255;0;0maaa\033[0m"},
+		{"html", blush.HTMLRenderer{}, style, `<span style="color: #ff0000; font-weight: bold; text-decoration: underline">aaa</span>`},
+		{
+			"html reverse", blush.HTMLRenderer{}, reversed,
+			`<span style="color: #000046; background-color: #ff0000; text-decoration: line-through">aaa</span>`,
+		},
+		{
+			"html attributes only", blush.HTMLRenderer{},
+			blush.Style{Colour: blush.NoColour, Attributes: blush.Italic | blush.Dim},
+			`<span style="opacity: 0.5; font-style: italic">aaa</span>`,
+		},
+		{"html classes", blush.HTMLRenderer{Classes: true}, style, `<span class="blush-fg-ff0000 blush-bold blush-underline">aaa</span>`},
+		{
+			"html classes reverse", blush.HTMLRenderer{Classes: true}, reversed,
+			`<span class="blush-fg-ff0000 blush-bg-000046 blush-reverse blush-strikethrough">aaa</span>`,
+		},
+		{"plain", blush.PlainRenderer{}, style, "aaa"},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.renderer.Render("aaa", []blush.Match{{Style: tc.style, End: 3}})
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestFindersWithStyle(t *testing.T) {
+	t.Parallel()
+	style := blush.Style{Colour: blush.Green, Attributes: blush.Bold}
+	want := "bb " + blush.Stylise("aaa", style) + " bb"
+	finders := []blush.Finder{
+		blush.NewExact("aaa", style),
+		blush.NewIexact("AAA", style),
+		blush.NewLocator("g,bold", "a{3}", false),
+	}
+	for _, f := range finders {
+		got, ok := f.Find("bb aaa bb")
+		assert.True(t, ok)
+		assert.Equal(t, want, got)
+		assert.Equal(t, style, f.(styler).Style())
+	}
+}
+
+// nolint:misspell // it's ok.
+func TestParseStyle(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name    string
+		spec    string
+		want    blush.Style
+		wantErr error
+		bad     string
+	}{
+		{"empty", "", blush.DefaultColour.Style(), nil, ""},
+		{"colour only", "red", blush.Red.Style(), nil, ""},
+		{"attributes", "red,bold,u", blush.Style{Colour: blush.Red, Attributes: blush.Bold | blush.Underline}, nil, ""},
+		{"background", "yl/bl+b", blush.Style{Colour: blush.Colour{Foreground: blush.FgYellow, Background: blush.FgBlack}, Attributes: blush.Bold}, nil, ""},
+		{"trailing separator", "red,", blush.Red.Style(), nil, ""},
+		{"bad colour", "gren", blush.NoStyle, blush.ErrInvalidColour, "gren"},
+		{"bad colour with attributes", "gren,bold", blush.NoStyle, blush.ErrInvalidColour, "gren"},
+		{"bad hex", "#ggg", blush.NoStyle, blush.ErrInvalidColour, "#ggg"},
+		{"bad attribute", "red,blinking", blush.NoStyle, blush.ErrInvalidAttribute, "blinking"},
+		{"bad short attribute", "r+x", blush.NoStyle, blush.ErrInvalidAttribute, "x"},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := blush.ParseStyle(tc.spec)
+			if tc.wantErr != nil {
+				assert.True(t, errors.Is(err, tc.wantErr))
+				assert.Contains(t, err.Error(), tc.bad)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
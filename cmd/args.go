@@ -1,26 +1,70 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/arsham/blush/blush"
+	"github.com/arsham/blush/internal/reader"
+	"github.com/arsham/blush/internal/tools"
+)
+
+// colourMode decides when the output is coloured.
+type colourMode int
+
+const (
+	// colourAuto colours the output only if the standard output is a terminal.
+	colourAuto colourMode = iota
+	colourAlways
+	colourNever
 )
 
 // Note that hasArgs, setFinders and setPaths methods of args are designed to
 // shrink the input as they go. Therefore the order of calls matters in some
 // cases.
 type args struct {
-	paths       []string
-	matches     []string
-	remaining   []string
-	finders     []blush.Finder
-	cut         bool
-	noFilename  bool
-	recursive   bool
-	insensitive bool
-	stdin       bool
+	paths        []string
+	matches      []string
+	remaining    []string
+	finders      []blush.Finder
+	patterns     []string // patterns of the finders, in the same order.
+	exclude      []blush.Finder
+	colourDepth  blush.ColourDepth
+	before       uint
+	after        uint
+	colour       colourMode
+	mode         blush.Mode
+	binary       blush.BinaryMode
+	encoding     reader.Encoding
+	outEncoding  reader.Encoding
+	cut          bool
+	invert       bool
+	noFilename   bool
+	lineNumber   bool
+	byteOffset   bool
+	onlyMatch    bool
+	count        bool
+	stats        bool
+	quiet        bool
+	withMatch    bool
+	withoutMatch bool
+	follow       bool
+	maxCount     uint
+	maxTotal     uint
+	tail         uint
+	recursive    bool
+	noIgnore     bool
+	hidden       bool
+	includeFiles []string
+	excludeFiles []string
+	excludeDirs  []string
+	types        []string
+	typeGlobs    map[string][]string
+	insensitive  bool
+	stdin        bool
 }
 
 // nolint:misspell // it's ok.
@@ -33,36 +77,369 @@ func newArgs(input ...string) (*args, error) {
 		return nil, errShowHelp
 	}
 	a.recursive = a.hasArgs("-R")
+	a.noIgnore = a.hasArgs("--no-ignore")
+	a.hidden = a.hasArgs("--hidden")
 	a.cut = a.hasArgs("-d", "--drop")
+	a.invert = a.hasArgs("-v", "--invert-match")
 	a.noFilename = a.hasArgs("-h", "--no-filename")
+	a.lineNumber = a.hasArgs("-n", "--line-number")
+	a.byteOffset = a.hasArgs("--byte-offset")
+	a.onlyMatch = a.hasArgs("-o", "--only-matching")
+	a.count = a.hasArgs("-c", "--count")
+	a.stats = a.hasArgs("--stats")
+	a.quiet = a.hasArgs("-q", "--quiet")
 	a.insensitive = a.hasArgs("-i")
+	if err := a.setMode(); err != nil {
+		return nil, err
+	}
+	if err := a.setListing(); err != nil {
+		return nil, err
+	}
+	if err := a.setFollow(); err != nil {
+		return nil, err
+	}
+	if err := a.setFileFilters(); err != nil {
+		return nil, err
+	}
+	if err := a.setContext(); err != nil {
+		return nil, err
+	}
+	if err := a.setLimits(); err != nil {
+		return nil, err
+	}
+	if err := a.setBinaryFiles(); err != nil {
+		return nil, err
+	}
+	if err := a.setEncodings(); err != nil {
+		return nil, err
+	}
+	if err := a.setColourDepth(); err != nil {
+		return nil, err
+	}
+	if err := a.setColourMode(); err != nil {
+		return nil, err
+	}
 
 	if stat, _ := os.Stdin.Stat(); (stat.Mode() & os.ModeCharDevice) == 0 {
 		a.stdin = true
 	} else if err := a.setPaths(); err != nil {
 		return nil, err
 	}
-	a.setFinders()
+	if err := a.setFinders(); err != nil {
+		return nil, err
+	}
 	return a, nil
 }
 
 // hasArgs removes any occurring `args` argument.
 func (a *args) hasArgs(args ...string) (found bool) {
-	remains := a.remaining
-LOOP:
-	for _, arg := range args {
-		for i, ar := range a.remaining {
-			if ar == arg {
-				remains = append(remains[:i], remains[i+1:]...)
-				found = true
-				if len(remains) == 0 {
-					break LOOP
+	flags := flagTokens(a.remaining)
+	remains := make([]string, 0, len(a.remaining))
+	for i, ar := range a.remaining {
+		if flags[i] && inStringSlice(ar, args) {
+			found = true
+			continue
+		}
+		remains = append(remains, ar)
+	}
+	a.remaining = remains
+	return found
+}
+
+// patternArgs are the arguments that take a pattern as their value.
+var patternArgs = []string{"-e", "--regexp", "--not"}
+
+// flagTokens reports which of the tokens are flags. The values of patternArgs
+// and any tokens after "--" are patterns even if they start with a dash.
+func flagTokens(tokens []string) []bool {
+	flags := make([]bool, len(tokens))
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		if !strings.HasPrefix(t, "-") {
+			continue
+		}
+		flags[i] = true
+		if t == "--" {
+			return flags
+		}
+		if inStringSlice(t, patternArgs) {
+			i++
+		}
+	}
+	return flags
+}
+
+// valueArg removes the first occurrence of any of the names and its value from
+// the remaining arguments, and returns the value. The value can be given as
+// the next argument or after an equal sign, for example "--name value" or
+// "--name=value". It returns ErrMissingValue if the name is the last argument.
+func (a *args) valueArg(names ...string) (value string, found bool, err error) {
+	flags := flagTokens(a.remaining)
+	for i, ar := range a.remaining {
+		if !flags[i] {
+			continue
+		}
+		for _, name := range names {
+			switch {
+			case ar == name:
+				if i+1 >= len(a.remaining) {
+					return "", false, fmt.Errorf("%w: %s", ErrMissingValue, name)
 				}
+				value = a.remaining[i+1]
+				a.remaining = append(a.remaining[:i], a.remaining[i+2:]...)
+				return value, true, nil
+			case strings.HasPrefix(ar, name+"="):
+				value = strings.TrimPrefix(ar, name+"=")
+				a.remaining = append(a.remaining[:i], a.remaining[i+1:]...)
+				return value, true, nil
 			}
 		}
 	}
+	return "", false, nil
+}
+
+// valuesArg removes all the occurrences of the names with their values, and
+// returns the values in order.
+func (a *args) valuesArg(names ...string) ([]string, error) {
+	var values []string
+	for {
+		v, ok, err := a.valueArg(names...)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return values, nil
+		}
+		values = append(values, v)
+	}
+}
+
+// nolint:misspell // it's ok.
+func (a *args) setColourDepth() error {
+	depth, ok, err := a.valueArg("--colour-depth", "--color-depth")
+	if err != nil || !ok {
+		return err
+	}
+	a.colourDepth, err = blush.ParseColourDepth(depth)
+	return err
+}
+
+// setColourMode removes the --colour=WHEN arguments and sets the colour mode
+// from the last one. WHEN can be auto, always or never, and a bare --colour
+// means auto. Unlike other arguments, the value can only be given after an
+// equal sign, otherwise it would be taken for a pattern.
+// nolint:misspell // it's ok.
+func (a *args) setColourMode() error {
+	flags := flagTokens(a.remaining)
+	remains := make([]string, 0, len(a.remaining))
+	for i, ar := range a.remaining {
+		name, when, hasValue := strings.Cut(ar, "=")
+		if !flags[i] || (name != "--colour" && name != "--color") {
+			remains = append(remains, ar)
+			continue
+		}
+		switch {
+		case !hasValue, when == "auto":
+			a.colour = colourAuto
+		case when == "always":
+			a.colour = colourAlways
+		case when == "never":
+			a.colour = colourNever
+		default:
+			return fmt.Errorf("%w: %s", ErrInvalidValue, ar)
+		}
+	}
 	a.remaining = remains
-	return found
+	return nil
+}
+
+// setMode sets the mode of the patterns from the -F and -E arguments. They
+// can't be used together.
+func (a *args) setMode() error {
+	fixed := a.hasArgs("-F", "--fixed-strings")
+	rx := a.hasArgs("-E", "--extended-regexp")
+	switch {
+	case fixed && rx:
+		return fmt.Errorf("%w: -F and -E", ErrConflictingArgs)
+	case fixed:
+		a.mode = blush.ModeFixed
+	case rx:
+		a.mode = blush.ModeRegexp
+	}
+	return nil
+}
+
+// setListing sets the listing mode from the -l and -L arguments, which can't
+// be used together.
+func (a *args) setListing() error {
+	a.withMatch = a.hasArgs("-l", "--files-with-matches")
+	a.withoutMatch = a.hasArgs("-L", "--files-without-match")
+	if a.withMatch && a.withoutMatch {
+		return fmt.Errorf("%w: -l and -L", ErrConflictingArgs)
+	}
+	return nil
+}
+
+// setFollow sets the follow mode from the -f argument. The counts and the
+// names of the files are printed when the files are read to the end, therefore
+// they can't be used with it.
+func (a *args) setFollow() error {
+	a.follow = a.hasArgs("-f", "--follow")
+	switch {
+	case !a.follow:
+	case a.count:
+		return fmt.Errorf("%w: -f and -c", ErrConflictingArgs)
+	case a.withMatch:
+		return fmt.Errorf("%w: -f and -l", ErrConflictingArgs)
+	case a.withoutMatch:
+		return fmt.Errorf("%w: -f and -L", ErrConflictingArgs)
+	}
+	return nil
+}
+
+// setFileFilters sets the globs of the files and the directories that are
+// searched from the --include, --exclude and --exclude-dir arguments, and the
+// file types from the -t arguments. The --type-add NAME:GLOB[,GLOB]... argument
+// adds the globs to the NAME type, which can be a new type.
+func (a *args) setFileFilters() error {
+	var err error
+	if a.includeFiles, err = a.valuesArg("--include"); err != nil {
+		return err
+	}
+	if a.excludeDirs, err = a.valuesArg("--exclude-dir"); err != nil {
+		return err
+	}
+	if a.excludeFiles, err = a.valuesArg("--exclude"); err != nil {
+		return err
+	}
+	adds, err := a.valuesArg("--type-add")
+	if err != nil {
+		return err
+	}
+	for _, add := range adds {
+		name, globs, ok := strings.Cut(add, ":")
+		if !ok || name == "" || globs == "" {
+			return fmt.Errorf("%w: --type-add %s", ErrInvalidValue, add)
+		}
+		if a.typeGlobs == nil {
+			a.typeGlobs = make(map[string][]string)
+		}
+		if _, ok := a.typeGlobs[name]; !ok {
+			a.typeGlobs[name] = append([]string(nil), tools.FileTypes[name]...)
+		}
+		a.typeGlobs[name] = append(a.typeGlobs[name], strings.Split(globs, ",")...)
+	}
+	if a.types, err = a.valuesArg("-t", "--type"); err != nil {
+		return err
+	}
+	for _, t := range a.types {
+		if _, ok := a.typeGlobs[t]; ok {
+			continue
+		}
+		if _, ok := tools.FileTypes[t]; !ok {
+			return fmt.Errorf("%w: -t %s", ErrInvalidValue, t)
+		}
+	}
+	return nil
+}
+
+// setContext sets the number of context lines from the -A, -B and -C
+// arguments. The -A and -B arguments take precedence over -C.
+func (a *args) setContext() error {
+	context, err := a.countArg(0, "-C", "--context")
+	if err != nil {
+		return err
+	}
+	if a.before, err = a.countArg(context, "-B", "--before-context"); err != nil {
+		return err
+	}
+	a.after, err = a.countArg(context, "-A", "--after-context")
+	return err
+}
+
+// setLimits sets the maximum number of the selected lines of each file from
+// the -m argument, and of all files from the --max-total argument. It also
+// sets the number of the last lines of each file that are read from the
+// --tail argument, which can't be used with -n.
+func (a *args) setLimits() error {
+	var err error
+	if a.maxCount, err = a.countArg(0, "-m", "--max-count"); err != nil {
+		return err
+	}
+	if a.maxTotal, err = a.countArg(0, "--max-total"); err != nil {
+		return err
+	}
+	if a.tail, err = a.countArg(0, "--tail"); err != nil {
+		return err
+	}
+	if a.tail > 0 && a.lineNumber {
+		// the lines before the tail are not read, therefore their numbers are
+		// not known.
+		return fmt.Errorf("%w: -n and --tail", ErrConflictingArgs)
+	}
+	return nil
+}
+
+// setBinaryFiles sets how the binary files are read from the --binary-files
+// argument, which can be skip, text or match. It is match by default.
+func (a *args) setBinaryFiles() error {
+	v, ok, err := a.valueArg("--binary-files")
+	if err != nil {
+		return err
+	}
+	switch {
+	case !ok, v == "match":
+		a.binary = blush.BinaryMatch
+	case v == "skip":
+		a.binary = blush.BinarySkip
+	case v == "text":
+		a.binary = blush.BinaryText
+	default:
+		return fmt.Errorf("%w: --binary-files %s", ErrInvalidValue, v)
+	}
+	return nil
+}
+
+// setEncodings sets the encoding of the input from the --encoding argument,
+// and the encoding of the output from the --output-encoding argument. The
+// input encoding is auto by default, and the output is not encoded.
+func (a *args) setEncodings() error {
+	var err error
+	if a.encoding, err = a.encodingArg("--encoding"); err != nil {
+		return err
+	}
+	a.outEncoding, err = a.encodingArg("--output-encoding")
+	return err
+}
+
+// encodingArg removes the first occurrence of the name and its value, and
+// returns the value as an encoding. It returns reader.Auto if the name is not
+// given.
+func (a *args) encodingArg(name string) (reader.Encoding, error) {
+	v, ok, err := a.valueArg(name)
+	if err != nil || !ok {
+		return reader.Auto, err
+	}
+	e, err := reader.ParseEncoding(v)
+	if err != nil {
+		return reader.Auto, fmt.Errorf("%w: %s %s", ErrInvalidValue, name, v)
+	}
+	return e, nil
+}
+
+// countArg removes the first occurrence of any of the names and its value, and
+// returns the value as a number. It returns def if none of the names are
+// given.
+func (a *args) countArg(def uint, names ...string) (uint, error) {
+	v, ok, err := a.valueArg(names...)
+	if err != nil || !ok {
+		return def, err
+	}
+	n, err := strconv.ParseUint(v, 10, 0)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s %s", ErrInvalidValue, names[0], v)
+	}
+	return uint(n), nil
 }
 
 // setPaths starts from the end of the slice and removes any paths/globs/files
@@ -73,6 +450,7 @@ func (a *args) setPaths() error {
 		counter  int
 		p, ret   []string
 		input    = a.remaining
+		flags    = flagTokens(a.remaining)
 	)
 	// going backwards from the end.
 	input = flip(input)
@@ -92,7 +470,7 @@ LOOP:
 			return err
 		}
 		switch {
-		case len(input) > i+1 && strings.HasPrefix(input[i+1], "-"):
+		case len(input) > i+1 && flags[len(input)-i-2] && !hasPatternValue(input[i+1]):
 			// In this case, the previous input was a flag argument, therefore
 			// it might have been a colouring command. That is why we are
 			// ignoring this item.
@@ -121,17 +499,103 @@ LOOP:
 	return nil
 }
 
-func (a *args) setFinders() {
-	var lastColour string
+// setFinders creates a finder for each pattern, with the colour of the last
+// colour argument before it. The --fg=COLOUR and --bg=COLOUR arguments are
+// combined until another colour argument is given. The value of a -e or a
+// --regexp argument is always a regexp, otherwise the mode of args decides.
+// The values of --not arguments are put in the exclude finders. It returns an
+// error that points to the argument if a pattern or the colour it uses is not
+// valid.
+func (a *args) setFinders() error {
+	var (
+		lastColour, colourArg, fg, bg string
+		flags                         = flagTokens(a.remaining)
+	)
 	a.finders = make([]blush.Finder, 0)
-	for _, token := range a.remaining {
-		if strings.HasPrefix(token, "-") {
-			lastColour = strings.TrimLeft(token, "-")
+	a.exclude = make([]blush.Finder, 0)
+	for i := 0; i < len(a.remaining); i++ {
+		token, mode := a.remaining[i], a.mode
+		switch {
+		case !flags[i]:
+		case token == "--":
+			continue
+		case token == "-e", token == "--regexp":
+			if i+1 >= len(a.remaining) {
+				return fmt.Errorf("%w: %s", ErrMissingValue, token)
+			}
+			i++
+			token, mode = a.remaining[i], blush.ModeRegexp
+		case strings.HasPrefix(token, "--regexp="):
+			token, mode = strings.TrimPrefix(token, "--regexp="), blush.ModeRegexp
+		case token == "--not":
+			if i+1 >= len(a.remaining) {
+				return fmt.Errorf("%w: %s", ErrMissingValue, token)
+			}
+			i++
+			if err := a.addExclude(a.remaining[i]); err != nil {
+				return err
+			}
+			continue
+		case strings.HasPrefix(token, "--not="):
+			if err := a.addExclude(strings.TrimPrefix(token, "--not=")); err != nil {
+				return err
+			}
+			continue
+		case strings.HasPrefix(token, "--fg="):
+			fg = strings.TrimPrefix(token, "--fg=")
+			lastColour, colourArg = joinColours(fg, bg), token
+			continue
+		case strings.HasPrefix(token, "--bg="):
+			bg = strings.TrimPrefix(token, "--bg=")
+			lastColour, colourArg = joinColours(fg, bg), token
 			continue
+		default:
+			lastColour, colourArg = strings.TrimLeft(token, "-"), token
+			fg, bg = "", ""
+			continue
+		}
+		if _, err := blush.ParseStyle(lastColour); err != nil {
+			return fmt.Errorf("argument %q: %w", colourArg, err)
+		}
+		l, err := blush.NewLocatorMode(mode, lastColour, token, a.insensitive)
+		if err != nil {
+			return fmt.Errorf("argument %q: %w", token, err)
 		}
-		l := blush.NewLocator(lastColour, token, a.insensitive)
 		a.finders = append(a.finders, l)
+		a.patterns = append(a.patterns, token)
+	}
+	return nil
+}
+
+// addExclude adds a finder for the pattern to the exclude finders.
+func (a *args) addExclude(pattern string) error {
+	l, err := blush.NewLocatorMode(a.mode, "", pattern, a.insensitive)
+	if err != nil {
+		return fmt.Errorf("argument %q: %w", pattern, err)
+	}
+	a.exclude = append(a.exclude, l)
+	return nil
+}
+
+// hasPatternValue reports whether the token is one of the patternArgs with
+// its value after an equal sign, for example "--not=pattern".
+func hasPatternValue(token string) bool {
+	name, _, ok := strings.Cut(token, "=")
+	return ok && inStringSlice(name, patternArgs)
+}
+
+// joinColours returns a FG/BG colour spec. The text attributes of both colours
+// are moved to the end of the spec.
+func joinColours(fg, bg string) string {
+	cut := func(s string) (colour, attrs string) {
+		if i := strings.IndexAny(s, ",+"); i >= 0 {
+			return s[:i], s[i:]
+		}
+		return s, ""
 	}
+	fgColour, fgAttrs := cut(fg)
+	bgColour, bgAttrs := cut(bg)
+	return fgColour + "/" + bgColour + fgAttrs + bgAttrs
 }
 
 func flip(s []string) []string {
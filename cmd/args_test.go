@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/alecthomas/assert"
+	"github.com/arsham/blush/blush"
 )
 
 func TestArgs(t *testing.T) {
@@ -135,8 +136,8 @@ func TestArgsHasArgs(t *testing.T) {
 		{[]string{"-a"}, []string{"-a"}, []string{}, true},
 		{[]string{"-a"}, []string{"-a", "-a"}, []string{}, true},
 		{[]string{"-a", "-b"}, []string{"-a"}, []string{"-b"}, true},
-		{[]string{"-a", "-c", "-b"}, []string{"-c"}, []string{"-a", "-b"}, true},
-		{[]string{"-a", "-c", "-b"}, []string{"-d"}, []string{"-a", "-c", "-b"}, false},
+		{[]string{"-a", "-x", "-b"}, []string{"-x"}, []string{"-a", "-b"}, true},
+		{[]string{"-a", "-x", "-b"}, []string{"-d"}, []string{"-a", "-x", "-b"}, false},
 	}
 	for i, tc := range tcs {
 		tc := tc
@@ -150,3 +151,107 @@ func TestArgsHasArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestArgsValueArg(t *testing.T) {
+	tcs := []struct {
+		name      string
+		input     []string
+		wantValue string
+		wantFound bool
+		wantErr   error
+		want      []string
+	}{
+		{"not found", []string{"a", "b"}, "", false, nil, []string{"a", "b"}},
+		{"separate", []string{"a", "--val", "v", "b"}, "v", true, nil, []string{"a", "b"}},
+		{"short", []string{"a", "-x", "v", "b"}, "v", true, nil, []string{"a", "b"}},
+		{"equal", []string{"a", "--val=v", "b"}, "v", true, nil, []string{"a", "b"}},
+		{"equal empty", []string{"a", "--val=", "b"}, "", true, nil, []string{"a", "b"}},
+		{"first one", []string{"--val=v", "-x", "w"}, "v", true, nil, []string{"-x", "w"}},
+		{"missing", []string{"a", "--val"}, "", false, ErrMissingValue, []string{"a", "--val"}},
+		{"prefix only", []string{"--value", "v"}, "", false, nil, []string{"--value", "v"}},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			a := &args{remaining: tc.input}
+			value, found, err := a.valueArg("--val", "-x")
+			if tc.wantErr != nil {
+				assert.True(t, errors.Is(err, tc.wantErr))
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tc.wantValue, value)
+			assert.Equal(t, tc.wantFound, found)
+			assert.True(t, stringSliceEq(a.remaining, tc.want))
+		})
+	}
+}
+
+// nolint:misspell // it's ok.
+func TestArgsColourDepth(t *testing.T) {
+	tcs := []struct {
+		name    string
+		input   []string
+		want    blush.ColourDepth
+		wantErr bool
+	}{
+		{"not set", []string{}, 0, false},
+		{"16", []string{"--colour-depth=16"}, blush.Depth16, false},
+		{"256", []string{"--colour-depth", "256"}, blush.Depth256, false},
+		{"truecolor", []string{"--color-depth=truecolor"}, blush.DepthTrueColour, false},
+		{"bad value", []string{"--colour-depth=12"}, 0, true},
+		{"no value", []string{"--colour-depth"}, 0, true},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			getPipe(t)
+			a, err := newArgs(tc.input...)
+			if tc.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, a)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, a.colourDepth)
+		})
+	}
+}
+
+func TestArgsFlagTokens(t *testing.T) {
+	tcs := []struct {
+		name  string
+		input []string
+		want  []bool
+	}{
+		{"empty", []string{}, []bool{}},
+		{"flags", []string{"-r", "aaa", "--blue"}, []bool{true, false, true}},
+		{"e value", []string{"-e", "-aaa", "-b"}, []bool{true, false, true}},
+		{"regexp value", []string{"--regexp", "-e", "-e", "-b"}, []bool{true, false, true, false}},
+		{"double dash", []string{"-r", "--", "-aaa", "--", "-b"}, []bool{true, true, false, false, false}},
+		{"not value", []string{"--not", "-aaa", "-b", "--not=-c"}, []bool{true, false, true, true}},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, flagTokens(tc.input))
+		})
+	}
+}
+
+func TestArgsMissingPattern(t *testing.T) {
+	getPipe(t)
+	_, err := newArgs("-r", "aaa", "-e")
+	assert.True(t, errors.Is(err, ErrMissingValue))
+
+	a, err := newArgs("-e", "-i", "-i")
+	assert.NoError(t, err)
+	assert.True(t, a.insensitive)
+	assert.Len(t, a.finders, 1)
+}
+
+func TestArgsMissingExclude(t *testing.T) {
+	getPipe(t)
+	_, err := newArgs("-r", "aaa", "--not")
+	assert.True(t, errors.Is(err, ErrMissingValue))
+}
@@ -1,9 +1,10 @@
 // Package cmd bootstraps the application.
 //
 // Main() reads the provided arguments from the command line and creates a
-// blush.Blush instance. If there is any error, it will terminate the
-// application with os.Exit(1), otherwise it then uses io.Copy() to write to
-// standard output and exits normally.
+// blush.Blush instance. If there is any error, it returns ExitError, otherwise
+// it then uses io.Copy() to write to standard output and returns ExitMatch if
+// any lines are selected, or ExitNoMatch if none are. The main function exits
+// with the returned code.
 //
 // GetBlush() returns an error if no arguments are provided or it can't find all
 // the passed files. Files should be last arguments, otherwise they are counted
@@ -10,4 +10,16 @@ var (
 	// ErrNoFilesFound is returned when the files pattern passed to the application
 	// doesn't match any existing files.
 	ErrNoFilesFound = errors.New("no files found")
+
+	// ErrMissingValue is returned when an argument that requires a value is the
+	// last argument.
+	ErrMissingValue = errors.New("missing value for argument")
+
+	// ErrInvalidValue is returned when the value of an argument is not one of
+	// the accepted values.
+	ErrInvalidValue = errors.New("invalid value for argument")
+
+	// ErrConflictingArgs is returned when two arguments that can't be used
+	// together are given.
+	ErrConflictingArgs = errors.New("conflicting arguments")
 )
@@ -53,6 +53,12 @@ func setup(t *testing.T, args string) (stdout, stderr *stdFile) {
 	os.Stdout = stdout.f
 	os.Stderr = stderr.f
 
+	// the expectations are in 256 colours.
+	t.Setenv("COLORTERM", "")
+	t.Setenv("TERM", "xterm-256color")
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("CLICOLOR_FORCE", "")
+
 	os.Args = []string{"blush"}
 	if len(args) > 1 {
 		os.Args = append(os.Args, strings.Split(args, " ")...)
@@ -1,38 +1,60 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"log"
 	"os"
+	"text/tabwriter"
+	"time"
 
 	"github.com/arsham/blush/blush"
 	"github.com/arsham/blush/internal/reader"
+	"github.com/arsham/blush/internal/tools"
+)
+
+// These are the exit codes of the application. They are the same as grep's.
+const (
+	ExitMatch   = 0 // at least one line is selected.
+	ExitNoMatch = 1 // no lines are selected.
+	ExitError   = 2 // an error has occurred.
 )
 
 // Main reads the provided arguments from the command line and creates a
-// blush.Blush instance.
-func Main() {
-	b, err := GetBlush(os.Args)
+// blush.Blush instance. It returns the exit code of the application, which is
+// ExitError if there is an error, even if some lines are selected. With -L, the
+// exit code is ExitMatch if any files are listed.
+func Main() int {
+	b, a, err := getBlush(os.Args)
 	if errors.Is(err, errShowHelp) {
 		fmt.Println(Usage)
-		return
+		return ExitMatch
 	}
 	if err != nil {
-		log.Fatalf("%s\n%s", err, Help)
-		return // this return statement should be here to support tests.
+		log.Printf("%s\n%s", err, Help)
+		return ExitError
 	}
-	defer func() {
-		if err := b.Close(); err != nil {
-			log.Fatal(err)
-		}
-	}()
 	sig := make(chan os.Signal, 1)
 	WaitForSignal(sig, os.Exit)
-	if _, err := io.Copy(os.Stdout, b); err != nil {
+	code := ExitNoMatch
+	if _, err := io.Copy(reader.NewEncoder(os.Stdout, a.outEncoding), b); err != nil {
 		log.Print(err)
+		code = ExitError
+	} else if selected(a, b.Stats()) {
+		code = ExitMatch
 	}
+	// the readers are closed before the statistics are printed, because in
+	// the quiet mode the rest of the input is not read.
+	if err := b.Close(); err != nil {
+		log.Print(err)
+		code = ExitError
+	}
+	if a.stats {
+		printStats(os.Stderr, a.patterns, b.Stats())
+	}
+	return code
 }
 
 // GetBlush returns an error if no arguments are provided or it can't find all
@@ -42,27 +64,125 @@ func Main() {
 //
 // The first argument will be dropped as it will be the application's name.
 func GetBlush(input []string) (*blush.Blush, error) {
+	b, _, err := getBlush(input)
+	return b, err
+}
+
+func getBlush(input []string) (*blush.Blush, *args, error) {
 	var (
 		r   io.ReadCloser = os.Stdin
 		a   *args
 		err error
 	)
 	if len(input) == 1 {
-		return nil, ErrNoInput
+		return nil, nil, ErrNoInput
 	}
 	if a, err = newArgs(input[1:]...); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	if !a.stdin {
-		r, err = reader.NewMultiReader(reader.WithPaths(a.paths, a.recursive))
+	if a.stdin {
+		r = reader.Tail(reader.Decode(r, a.encoding), a.tail)
+	} else {
+		confs := []reader.Conf{
+			reader.WithWalker(a.paths, tools.Walker{
+				Recursive:  a.recursive,
+				NoIgnore:   a.noIgnore,
+				Hidden:     a.hidden,
+				Include:    a.includeFiles,
+				Exclude:    a.excludeFiles,
+				ExcludeDir: a.excludeDirs,
+				Types:      a.types,
+				TypeGlobs:  a.typeGlobs,
+			}),
+			reader.WithEncoding(a.encoding),
+			reader.WithTail(a.tail),
+		}
+		if a.follow {
+			confs = append(confs, reader.WithFollow(context.Background(), reader.DefaultFollowInterval))
+		}
+		r, err = reader.NewMultiReader(confs...)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 	return &blush.Blush{
-		Finders:      a.finders,
-		Reader:       r,
-		Drop:         a.cut,
-		WithFileName: !a.noFilename,
-	}, nil
+		Finders:           a.finders,
+		Exclude:           a.exclude,
+		ContextBefore:     a.before,
+		ContextAfter:      a.after,
+		Reader:            r,
+		Renderer:          renderer(a, os.Getenv, os.Stdout),
+		Drop:              a.cut,
+		Invert:            a.invert,
+		WithFileName:      !a.noFilename,
+		LineNumber:        a.lineNumber,
+		ByteOffset:        a.byteOffset,
+		OnlyMatching:      a.onlyMatch,
+		Count:             a.count,
+		Quiet:             a.quiet,
+		FilesWithMatches:  a.withMatch,
+		FilesWithoutMatch: a.withoutMatch,
+		MaxCount:          a.maxCount,
+		MaxTotal:          a.maxTotal,
+		BinaryFiles:       a.binary,
+	}, a, nil
+}
+
+// selected reports whether the application has found what it was asked for.
+func selected(a *args, s blush.Stats) bool {
+	if a.withoutMatch {
+		return s.Listed > 0
+	}
+	return s.Matched > 0
+}
+
+// printStats writes the statistics of the input in a table. The patterns are
+// the patterns of the finders in the stats, in the same order.
+func printStats(w io.Writer, patterns []string, s blush.Stats) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PATTERN\tMATCHES\tLINES")
+	for i, f := range s.Finders {
+		pattern := fmt.Sprint(f.Finder)
+		if i < len(patterns) {
+			pattern = patterns[i]
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\n", pattern, f.Matches, f.Lines)
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "files: %d, lines: %d, bytes: %d, elapsed: %s\n",
+		s.Files, s.Lines, s.Bytes, s.Elapsed.Round(time.Microsecond))
+}
+
+// renderer returns a Renderer that doesn't colour the output if the colours
+// are not wanted. In the auto mode, the output is coloured if out is a
+// terminal. The NO_COLOR environment variable turns the colours off and the
+// CLICOLOR_FORCE turns them on in this mode.
+func renderer(a *args, getenv func(string) string, out *os.File) blush.Renderer {
+	switch a.colour {
+	case colourNever:
+		return blush.PlainRenderer{}
+	case colourAuto:
+		if !wantsColour(getenv, out) {
+			return blush.PlainRenderer{}
+		}
+	}
+	depth := a.colourDepth
+	if depth == 0 {
+		depth = blush.DetectColourDepth(getenv)
+	}
+	return blush.NewRenderer(depth)
+}
+
+func wantsColour(getenv func(string) string, out *os.File) bool {
+	if force := getenv("CLICOLOR_FORCE"); force != "" && force != "0" {
+		return true
+	}
+	if getenv("NO_COLOR") != "" {
+		return false
+	}
+	stat, err := out.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
 }
@@ -1,14 +1,21 @@
 package cmd_test
 
 import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
 	"fmt"
 	"os"
 	"path"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/alecthomas/assert"
 	"github.com/arsham/blush/blush"
 	"github.com/arsham/blush/cmd"
+	"github.com/arsham/blush/internal/reader"
 )
 
 func TestMainHelp(t *testing.T) {
@@ -43,10 +50,10 @@ func TestMainMatch(t *testing.T) {
 		name  string
 		input string
 	}{
-		{"exact sensitive", "-b TOKEN"},
-		{"exact insensitive", "-i -b TOKEN"},
-		{"regexp sensitive", "-b TOK[EN]{2}"},
-		{"regexp insensitive", "-i -b tok[en]{2}"},
+		{"exact sensitive", "--colour=always -b TOKEN"},
+		{"exact insensitive", "--colour=always -i -b TOKEN"},
+		{"regexp sensitive", "--colour=always -b TOK[EN]{2}"},
+		{"regexp insensitive", "--colour=always -i -b tok[en]{2}"},
 	}
 	for _, tc := range tcs {
 		tc := tc
@@ -159,6 +166,34 @@ func TestColourArgs(t *testing.T) {
 			blush.NewExact(aaa, blush.Yellow),
 			blush.NewExact(bbb, blush.Yellow),
 		}},
+		{"background", []string{"-yl/bl", "aaa", "--#ff0/#333", "bbb", "/"}, []blush.Finder{
+			blush.NewExact(aaa, blush.Colour{Foreground: blush.FgYellow, Background: blush.FgBlack}),
+			blush.NewExact(bbb, blush.Colour{Foreground: blush.FgYellow, Background: blush.RGB{R: 51, G: 51, B: 51}}),
+		}},
+		{"fg and bg", []string{"--fg=#ff0", "--bg=#333", "aaa", "/"}, []blush.Finder{
+			blush.NewExact(aaa, blush.Colour{Foreground: blush.FgYellow, Background: blush.RGB{R: 51, G: 51, B: 51}}),
+		}},
+		{"bg only", []string{"--bg=r", "aaa", "/"}, []blush.Finder{
+			blush.NewExact(aaa, blush.Colour{Foreground: blush.NoRGB, Background: blush.FgRed}),
+		}},
+		{"fg and bg are combined", []string{"--fg=yl", "aaa", "--bg=bl", "bbb", "/"}, []blush.Finder{
+			blush.NewExact(aaa, blush.Yellow),
+			blush.NewExact(bbb, blush.Colour{Foreground: blush.FgYellow, Background: blush.FgBlack}),
+		}},
+		{"fg and bg are reset", []string{"--fg=yl", "--bg=bl", "aaa", "-r", "bbb", "/"}, []blush.Finder{
+			blush.NewExact(aaa, blush.Colour{Foreground: blush.FgYellow, Background: blush.FgBlack}),
+			blush.NewExact(bbb, blush.Red),
+		}},
+		{"fg and bg attributes", []string{"--fg=yl,bold", "--bg=bl+u", "aaa", "/"}, []blush.Finder{
+			blush.NewExact(aaa, blush.Style{
+				Colour:     blush.Colour{Foreground: blush.FgYellow, Background: blush.FgBlack},
+				Attributes: blush.Bold | blush.Underline,
+			}),
+		}},
+		{"attributes", []string{"--red,bold,underline", "aaa", "-g+u", "bbb", "/"}, []blush.Finder{
+			blush.NewExact(aaa, blush.Style{Colour: blush.Red, Attributes: blush.Bold | blush.Underline}),
+			blush.NewExact(bbb, blush.Style{Colour: blush.Green, Attributes: blush.Underline}),
+		}},
 	}
 
 	for _, tc := range tcs {
@@ -194,3 +229,932 @@ func TestWithFilename(t *testing.T) {
 		})
 	}
 }
+
+// nolint:misspell // it's ok.
+func TestColourDepth(t *testing.T) {
+	tcs := []struct {
+		name      string
+		colorterm string
+		term      string
+		input     []string
+		want      blush.Renderer
+	}{
+		{"detect 256", "", "xterm-256color", []string{"blush", "/"}, blush.ANSIRenderer{}},
+		{"detect true colour", "truecolor", "xterm", []string{"blush", "/"}, blush.TrueColourRenderer{}},
+		{"detect 16", "", "linux", []string{"blush", "/"}, blush.ANSI16Renderer{}},
+		{"override", "truecolor", "", []string{"blush", "--colour-depth=16", "/"}, blush.ANSI16Renderer{}},
+		{"override american", "", "linux", []string{"blush", "--color-depth", "truecolor", "/"}, blush.TrueColourRenderer{}},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("COLORTERM", tc.colorterm)
+			t.Setenv("TERM", tc.term)
+			t.Setenv("CLICOLOR_FORCE", "1")
+			b, err := cmd.GetBlush(tc.input)
+			assert.NoError(t, err)
+			assert.NotNil(t, b)
+			assert.Equal(t, tc.want, b.Renderer)
+		})
+	}
+}
+
+// The standard output is not a terminal in tests.
+// nolint:misspell // it's ok.
+func TestColourMode(t *testing.T) {
+	tcs := []struct {
+		name    string
+		noColor string
+		force   string
+		input   []string
+		want    blush.Renderer
+	}{
+		{"default", "", "", []string{"blush", "/"}, blush.PlainRenderer{}},
+		{"auto", "", "", []string{"blush", "--colour=auto", "/"}, blush.PlainRenderer{}},
+		{"bare", "", "", []string{"blush", "--colour", "/"}, blush.PlainRenderer{}},
+		{"always", "", "", []string{"blush", "--colour=always", "/"}, blush.ANSIRenderer{}},
+		{"always american", "", "", []string{"blush", "--color=always", "/"}, blush.ANSIRenderer{}},
+		{"never", "", "", []string{"blush", "--colour=never", "/"}, blush.PlainRenderer{}},
+		{"never forced", "", "1", []string{"blush", "--colour=never", "/"}, blush.PlainRenderer{}},
+		{"forced", "", "1", []string{"blush", "/"}, blush.ANSIRenderer{}},
+		{"forced zero", "", "0", []string{"blush", "/"}, blush.PlainRenderer{}},
+		{"no color", "1", "", []string{"blush", "/"}, blush.PlainRenderer{}},
+		{"no color always", "1", "", []string{"blush", "--colour=always", "/"}, blush.ANSIRenderer{}},
+		{"last one wins", "", "", []string{"blush", "--colour=always", "--colour=never", "/"}, blush.PlainRenderer{}},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("COLORTERM", "")
+			t.Setenv("TERM", "xterm-256color")
+			t.Setenv("NO_COLOR", tc.noColor)
+			t.Setenv("CLICOLOR_FORCE", tc.force)
+			b, err := cmd.GetBlush(tc.input)
+			assert.NoError(t, err)
+			assert.NotNil(t, b)
+			assert.Equal(t, tc.want, b.Renderer)
+		})
+	}
+}
+
+func TestColourModeError(t *testing.T) {
+	b, err := cmd.GetBlush([]string{"blush", "--colour=sometimes", "/"})
+	assert.True(t, errors.Is(err, cmd.ErrInvalidValue))
+	assert.Contains(t, err.Error(), "--colour=sometimes")
+	assert.Nil(t, b)
+}
+
+func TestInvalidFinderArgs(t *testing.T) {
+	tcs := []struct {
+		name    string
+		input   []string
+		arg     string
+		wantErr error
+	}{
+		{"colour typo", []string{"--gren", "aaa", "/"}, "--gren", blush.ErrInvalidColour},
+		{"bad hex", []string{"-#ggg", "aaa", "/"}, "-#ggg", blush.ErrInvalidColour},
+		{"bad background", []string{"-r/gren", "aaa", "/"}, "-r/gren", blush.ErrInvalidColour},
+		{"bad fg", []string{"--fg=gren", "aaa", "/"}, "--fg=gren", blush.ErrInvalidColour},
+		{"bad bg", []string{"--fg=r", "--bg=#ggg", "aaa", "/"}, "--bg=#ggg", blush.ErrInvalidColour},
+		{"bad attribute", []string{"-r,blinking", "aaa", "/"}, "-r,blinking", blush.ErrInvalidAttribute},
+		{"bad pattern", []string{"-r", "a(.b", "/"}, "a(.b", blush.ErrInvalidPattern},
+		{"bad insensitive pattern", []string{"-i", "a(.b", "/"}, "a(.b", blush.ErrInvalidPattern},
+		{"second pattern", []string{"-r", "aaa", "-g", "*bbb", "/"}, "*bbb", blush.ErrInvalidPattern},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			input := append([]string{"blush"}, tc.input...)
+			b, err := cmd.GetBlush(input)
+			assert.True(t, errors.Is(err, tc.wantErr))
+			assert.Contains(t, err.Error(), fmt.Sprintf("%q", tc.arg))
+			assert.Nil(t, b)
+		})
+	}
+}
+
+func TestPatternModes(t *testing.T) {
+	rx := func(colour blush.Colour, pattern string, insensitive bool) blush.Finder {
+		t.Helper()
+		l, err := blush.NewLocatorMode(blush.ModeRegexp, "", pattern, insensitive)
+		assert.NoError(t, err)
+		return blush.NewRx(l.(blush.Rx).Regexp, colour)
+	}
+	tcs := []struct {
+		name  string
+		input []string
+		want  []blush.Finder
+	}{
+		{"auto literal", []string{"-r", "aaa", "/"}, []blush.Finder{
+			blush.NewExact("aaa", blush.Red),
+		}},
+		{"auto regexp", []string{"-r", "aaa|bbb", "/"}, []blush.Finder{
+			rx(blush.Red, "aaa|bbb", false),
+		}},
+		{"fixed", []string{"-F", "-r", "1.5", "[WARN]", "/"}, []blush.Finder{
+			blush.NewExact("1.5", blush.Red),
+			blush.NewExact("[WARN]", blush.Red),
+		}},
+		{"fixed long", []string{"--fixed-strings", "a(.b", "/"}, []blush.Finder{
+			blush.NewExact("a(.b", blush.DefaultColour),
+		}},
+		{"fixed insensitive", []string{"-F", "-i", "a.b", "/"}, []blush.Finder{
+			blush.NewIexact("a.b", blush.DefaultColour),
+		}},
+		{"regexp", []string{"-E", "-g", "aaa", "/"}, []blush.Finder{
+			rx(blush.Green, "aaa", false),
+		}},
+		{"regexp long", []string{"--extended-regexp", "aaa", "/"}, []blush.Finder{
+			rx(blush.DefaultColour, "aaa", false),
+		}},
+		{"e flag", []string{"-F", "-r", "-e", "a.b", "c.d", "/"}, []blush.Finder{
+			rx(blush.Red, "a.b", false),
+			blush.NewExact("c.d", blush.Red),
+		}},
+		{"e flag long", []string{"-b", "--regexp", "aaa", "--regexp=bbb", "/"}, []blush.Finder{
+			rx(blush.Blue, "aaa", false),
+			rx(blush.Blue, "bbb", false),
+		}},
+		{"e flag insensitive", []string{"-i", "-e", "aaa", "/"}, []blush.Finder{
+			rx(blush.DefaultColour, "aaa", true),
+		}},
+		{"e flag with a dash", []string{"-r", "-e", "-aaa", "/"}, []blush.Finder{
+			rx(blush.Red, "-aaa", false),
+		}},
+		{"e flag with a flag name", []string{"-r", "-e", "-i", "/"}, []blush.Finder{
+			rx(blush.Red, "-i", false),
+		}},
+		{"double dash", []string{"-r", "--", "-aaa", "--bbb", "/"}, []blush.Finder{
+			blush.NewExact("-aaa", blush.Red),
+			blush.NewExact("--bbb", blush.Red),
+		}},
+		{"double dash flags", []string{"-F", "-g", "--", "-i", "-F", "a.b", "/"}, []blush.Finder{
+			blush.NewExact("-i", blush.Green),
+			blush.NewExact("-F", blush.Green),
+			blush.NewExact("a.b", blush.Green),
+		}},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			input := append([]string{"blush"}, tc.input...)
+			b, err := cmd.GetBlush(input)
+			assert.NoError(t, err)
+			assert.NotNil(t, b)
+			assert.Len(t, b.Finders, len(tc.want))
+			assert.True(t, argsEqual(b.Finders, tc.want))
+		})
+	}
+}
+
+func TestPatternModeErrors(t *testing.T) {
+	b, err := cmd.GetBlush([]string{"blush", "-F", "-E", "aaa", "/"})
+	assert.True(t, errors.Is(err, cmd.ErrConflictingArgs))
+	assert.Nil(t, b)
+
+	b, err = cmd.GetBlush([]string{"blush", "-E", "a(", "/"})
+	assert.True(t, errors.Is(err, blush.ErrInvalidPattern))
+	assert.Nil(t, b)
+}
+
+func TestInvertMatch(t *testing.T) {
+	tcs := []struct {
+		name  string
+		input []string
+		want  bool
+	}{
+		{"not set", []string{"blush", "aaa", "/"}, false},
+		{"short", []string{"blush", "-v", "aaa", "/"}, true},
+		{"long", []string{"blush", "--invert-match", "aaa", "/"}, true},
+		{"pattern", []string{"blush", "-e", "-v", "/"}, false},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := cmd.GetBlush(tc.input)
+			assert.NoError(t, err)
+			assert.NotNil(t, b)
+			assert.Equal(t, tc.want, b.Invert)
+		})
+	}
+}
+
+func TestExcludeArgs(t *testing.T) {
+	aaa := "aaa"
+	bbb := "bbb"
+	tcs := []struct {
+		name        string
+		input       []string
+		wantFinders []blush.Finder
+		wantExclude []blush.Finder
+	}{
+		{"only not", []string{"--not", "aaa", "/"}, []blush.Finder{}, []blush.Finder{
+			blush.NewExact(aaa, blush.DefaultColour),
+		}},
+		{"not with equal", []string{"--not=aaa", "/"}, []blush.Finder{}, []blush.Finder{
+			blush.NewExact(aaa, blush.DefaultColour),
+		}},
+		{"with finders", []string{"-r", "aaa", "--not", "bbb", "/"}, []blush.Finder{
+			blush.NewExact(aaa, blush.Red),
+		}, []blush.Finder{
+			blush.NewExact(bbb, blush.DefaultColour),
+		}},
+		{"colour continues", []string{"-r", "--not", "bbb", "aaa", "/"}, []blush.Finder{
+			blush.NewExact(aaa, blush.Red),
+		}, []blush.Finder{
+			blush.NewExact(bbb, blush.DefaultColour),
+		}},
+		{"dash", []string{"--not", "-r", "aaa", "/"}, []blush.Finder{
+			blush.NewExact(aaa, blush.DefaultColour),
+		}, []blush.Finder{
+			blush.NewExact("-r", blush.DefaultColour),
+		}},
+		{"insensitive", []string{"-i", "--not", "aaa", "/"}, []blush.Finder{}, []blush.Finder{
+			blush.NewIexact(aaa, blush.DefaultColour),
+		}},
+		{"fixed", []string{"-F", "--not", "a.a", "/"}, []blush.Finder{}, []blush.Finder{
+			blush.NewExact("a.a", blush.DefaultColour),
+		}},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			input := append([]string{"blush"}, tc.input...)
+			b, err := cmd.GetBlush(input)
+			assert.NoError(t, err)
+			assert.NotNil(t, b)
+			assert.Len(t, b.Finders, len(tc.wantFinders))
+			assert.True(t, argsEqual(b.Finders, tc.wantFinders))
+			assert.Len(t, b.Exclude, len(tc.wantExclude))
+			assert.True(t, argsEqual(b.Exclude, tc.wantExclude))
+		})
+	}
+}
+
+func TestExcludeArgsErrors(t *testing.T) {
+	b, err := cmd.GetBlush([]string{"blush", "-E", "--not", "a(", "/"})
+	assert.True(t, errors.Is(err, blush.ErrInvalidPattern))
+	assert.Contains(t, err.Error(), `"a("`)
+	assert.Nil(t, b)
+}
+
+func TestMainExclude(t *testing.T) {
+	pwd, err := os.Getwd()
+	assert.NoError(t, err)
+	location := path.Join(pwd, "../blush/testdata")
+
+	stdout, stderr := setup(t, fmt.Sprintf("--not %s %s", leaveMeHere, location))
+	cmd.Main()
+	assert.Empty(t, stderr.String())
+	assert.NotEmpty(t, stdout.String())
+	assert.Contains(t, stdout.String(), "TOKEN")
+	assert.NotContains(t, stdout.String(), leaveMeHere)
+
+	stdout, stderr = setup(t, fmt.Sprintf("-v TOKEN %s", location))
+	cmd.Main()
+	assert.Empty(t, stderr.String())
+	assert.NotEmpty(t, stdout.String())
+	assert.NotContains(t, stdout.String(), "TOKEN")
+}
+
+func TestContextArgs(t *testing.T) {
+	tcs := []struct {
+		name       string
+		input      []string
+		wantBefore uint
+		wantAfter  uint
+	}{
+		{"not set", []string{"aaa", "/"}, 0, 0},
+		{"after", []string{"-A", "2", "aaa", "/"}, 0, 2},
+		{"after long", []string{"--after-context=2", "aaa", "/"}, 0, 2},
+		{"before", []string{"-B", "3", "aaa", "/"}, 3, 0},
+		{"before long", []string{"--before-context", "3", "aaa", "/"}, 3, 0},
+		{"context", []string{"-C", "4", "aaa", "/"}, 4, 4},
+		{"context long", []string{"--context=4", "aaa", "/"}, 4, 4},
+		{"context and after", []string{"-C", "4", "-A", "1", "aaa", "/"}, 4, 1},
+		{"before and context", []string{"-B", "1", "-C", "4", "aaa", "/"}, 1, 4},
+		{"zero", []string{"-A", "0", "aaa", "/"}, 0, 0},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			input := append([]string{"blush"}, tc.input...)
+			b, err := cmd.GetBlush(input)
+			assert.NoError(t, err)
+			assert.NotNil(t, b)
+			assert.Equal(t, tc.wantBefore, b.ContextBefore)
+			assert.Equal(t, tc.wantAfter, b.ContextAfter)
+		})
+	}
+}
+
+func TestContextArgsErrors(t *testing.T) {
+	tcs := []struct {
+		name  string
+		input []string
+	}{
+		{"not a number", []string{"-A", "two", "aaa", "/"}},
+		{"negative", []string{"-B=-1", "aaa", "/"}},
+		{"bad context", []string{"--context=x", "aaa", "/"}},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			input := append([]string{"blush"}, tc.input...)
+			b, err := cmd.GetBlush(input)
+			assert.True(t, errors.Is(err, cmd.ErrInvalidValue))
+			assert.Nil(t, b)
+		})
+	}
+}
+
+func TestMainContext(t *testing.T) {
+	dir := t.TempDir()
+	file := path.Join(dir, "file.txt")
+	err := os.WriteFile(file, []byte("one\ntwo\nthree\nMATCH\nfour\nfive\n"), 0o600)
+	assert.NoError(t, err)
+
+	stdout, stderr := setup(t, fmt.Sprintf("--colour=never -h -d -B 1 -A 1 MATCH %s", file))
+	cmd.Main()
+	assert.Empty(t, stderr.String())
+	assert.Equal(t, "three\nMATCH\nfour\n", stdout.String())
+}
+
+func TestLineNumberArgs(t *testing.T) {
+	tcs := []struct {
+		name           string
+		input          []string
+		wantLineNumber bool
+		wantByteOffset bool
+	}{
+		{"not set", []string{"blush", "aaa", "/"}, false, false},
+		{"line number", []string{"blush", "-n", "aaa", "/"}, true, false},
+		{"line number long", []string{"blush", "--line-number", "aaa", "/"}, true, false},
+		{"byte offset", []string{"blush", "--byte-offset", "aaa", "/"}, false, true},
+		{"both", []string{"blush", "-n", "--byte-offset", "aaa", "/"}, true, true},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := cmd.GetBlush(tc.input)
+			assert.NoError(t, err)
+			assert.NotNil(t, b)
+			assert.Equal(t, tc.wantLineNumber, b.LineNumber)
+			assert.Equal(t, tc.wantByteOffset, b.ByteOffset)
+		})
+	}
+
+	b, err := cmd.GetBlush([]string{"blush", "-b", "aaa", "/"})
+	assert.NoError(t, err)
+	assert.False(t, b.ByteOffset)
+	assert.True(t, argsEqual(b.Finders, []blush.Finder{blush.NewExact("aaa", blush.Blue)}))
+}
+
+func TestMainLineNumber(t *testing.T) {
+	dir := t.TempDir()
+	file1 := path.Join(dir, "one.txt")
+	file2 := path.Join(dir, "two.txt")
+	err := os.WriteFile(file1, []byte("aaa\nMATCH\nbbb"), 0o600)
+	assert.NoError(t, err)
+	err = os.WriteFile(file2, []byte("MATCH\nccc\n"), 0o600)
+	assert.NoError(t, err)
+
+	stdout, stderr := setup(t, fmt.Sprintf("--colour=never -d -n --byte-offset MATCH %s %s", file1, file2))
+	cmd.Main()
+	assert.Empty(t, stderr.String())
+	want := fmt.Sprintf("%s:2:4: MATCH\n%s:1:0: MATCH\n", file1, file2)
+	assert.Equal(t, want, stdout.String())
+}
+
+func TestMainOnlyMatching(t *testing.T) {
+	dir := t.TempDir()
+	file := path.Join(dir, "file.txt")
+	err := os.WriteFile(file, []byte("id=123 and id=456\nnothing\nid=789\n"), 0o600)
+	assert.NoError(t, err)
+
+	for _, arg := range []string{"-o", "--only-matching"} {
+		stdout, stderr := setup(t, fmt.Sprintf("--colour=never -h %s -n id=[0-9]+ %s", arg, file))
+		cmd.Main()
+		assert.Empty(t, stderr.String())
+		assert.Equal(t, "1: id=123\n1: id=456\n3: id=789\n", stdout.String())
+	}
+}
+
+func TestCountArgs(t *testing.T) {
+	for _, arg := range []string{"-c", "--count"} {
+		b, err := cmd.GetBlush([]string{"blush", arg, "aaa", "/"})
+		assert.NoError(t, err)
+		assert.True(t, b.Count)
+	}
+	b, err := cmd.GetBlush([]string{"blush", "aaa", "/"})
+	assert.NoError(t, err)
+	assert.False(t, b.Count)
+
+	b, err = cmd.GetBlush([]string{"blush", "-cy", "aaa", "/"})
+	assert.NoError(t, err)
+	assert.False(t, b.Count)
+	assert.True(t, argsEqual(b.Finders, []blush.Finder{blush.NewExact("aaa", blush.Cyan)}))
+}
+
+func TestMainCountStats(t *testing.T) {
+	dir := t.TempDir()
+	file1 := path.Join(dir, "one.txt")
+	file2 := path.Join(dir, "two.txt")
+	err := os.WriteFile(file1, []byte("aaa bbb aaa\nccc\n"), 0o600)
+	assert.NoError(t, err)
+	err = os.WriteFile(file2, []byte("bbb\n"), 0o600)
+	assert.NoError(t, err)
+
+	stdout, stderr := setup(t, fmt.Sprintf("--colour=never -c --stats -r aaa -b b.b %s %s", file1, file2))
+	cmd.Main()
+	assert.Equal(t, fmt.Sprintf("%s: 1\n%s: 1\n", file1, file2), stdout.String())
+	lines := strings.Split(stderr.String(), "\n")
+	assert.Len(t, lines, 5)
+	assert.Equal(t, "PATTERN  MATCHES  LINES", lines[0])
+	assert.Equal(t, "aaa      2        1", lines[1])
+	assert.Equal(t, "b.b      2        2", lines[2])
+	assert.True(t, strings.HasPrefix(lines[3], "files: 2, lines: 3, bytes: 20, elapsed: "))
+
+	stdout, stderr = setup(t, fmt.Sprintf("--colour=never -h aaa %s", file1))
+	cmd.Main()
+	assert.Empty(t, stderr.String())
+	assert.Equal(t, "aaa bbb aaa\nccc\n", stdout.String())
+}
+
+func TestMainExitCode(t *testing.T) {
+	dir := t.TempDir()
+	file := path.Join(dir, "file.txt")
+	err := os.WriteFile(file, []byte("aaa\nbbb\n"), 0o600)
+	assert.NoError(t, err)
+
+	tcs := []struct {
+		name string
+		args string
+		want int
+	}{
+		{"help", "--help", cmd.ExitMatch},
+		{"match", "-d aaa", cmd.ExitMatch},
+		{"no match", "-d ccc", cmd.ExitNoMatch},
+		{"no match no cut", "ccc", cmd.ExitNoMatch},
+		{"invert", "-v aaa", cmd.ExitMatch},
+		{"invert no match", "-v aaa -e b", cmd.ExitNoMatch},
+		{"count", "-c ccc", cmd.ExitNoMatch},
+		{"bad argument", "--colour-depth=12 aaa", cmd.ExitError},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			setup(t, fmt.Sprintf("--colour=never %s %s", tc.args, file))
+			assert.Equal(t, tc.want, cmd.Main())
+		})
+	}
+}
+
+func TestMainQuiet(t *testing.T) {
+	dir := t.TempDir()
+	file1 := path.Join(dir, "one.txt")
+	file2 := path.Join(dir, "two.txt")
+	err := os.WriteFile(file1, []byte("aaa\nbbb\n"), 0o600)
+	assert.NoError(t, err)
+	err = os.WriteFile(file2, []byte("ccc\n"), 0o600)
+	assert.NoError(t, err)
+
+	for _, arg := range []string{"-q", "--quiet"} {
+		stdout, stderr := setup(t, fmt.Sprintf("%s -d aaa %s %s", arg, file1, file2))
+		assert.Equal(t, cmd.ExitMatch, cmd.Main())
+		assert.Empty(t, stdout.String())
+		assert.Empty(t, stderr.String())
+
+		stdout, stderr = setup(t, fmt.Sprintf("%s -c ddd %s %s", arg, file1, file2))
+		assert.Equal(t, cmd.ExitNoMatch, cmd.Main())
+		assert.Empty(t, stdout.String())
+		assert.Empty(t, stderr.String())
+	}
+
+	stdout, stderr := setup(t, fmt.Sprintf("-q --stats bbb %s %s", file1, file2))
+	assert.Equal(t, cmd.ExitMatch, cmd.Main())
+	assert.Empty(t, stdout.String())
+	assert.Contains(t, stderr.String(), "files: 1, lines: 2, bytes: 8")
+}
+
+func TestMainFiles(t *testing.T) {
+	dir := t.TempDir()
+	file1 := path.Join(dir, "one.txt")
+	file2 := path.Join(dir, "two.txt")
+	err := os.WriteFile(file1, []byte("aaa\nbbb\n"), 0o600)
+	assert.NoError(t, err)
+	err = os.WriteFile(file2, []byte("ccc\n"), 0o600)
+	assert.NoError(t, err)
+
+	tcs := []struct {
+		name     string
+		args     string
+		want     string
+		wantCode int
+	}{
+		{"with matches", "-l aaa", file1 + "\n", cmd.ExitMatch},
+		{"with matches long", "--files-with-matches ccc", file2 + "\n", cmd.ExitMatch},
+		{"with matches none", "-l ddd", "", cmd.ExitNoMatch},
+		{"without match", "-L aaa", file2 + "\n", cmd.ExitMatch},
+		{"without match long", "--files-without-match ddd", file1 + "\n" + file2 + "\n", cmd.ExitMatch},
+		{"without match none", "-L -e a -e c", "", cmd.ExitNoMatch},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			stdout, stderr := setup(t, fmt.Sprintf("--colour=never %s %s %s", tc.args, file1, file2))
+			assert.Equal(t, tc.wantCode, cmd.Main())
+			assert.Empty(t, stderr.String())
+			assert.Equal(t, tc.want, stdout.String())
+		})
+	}
+
+	_, err = cmd.GetBlush([]string{"blush", "-l", "-L", "aaa", file1})
+	assert.True(t, errors.Is(err, cmd.ErrConflictingArgs))
+}
+
+func TestLimitArgs(t *testing.T) {
+	tcs := []struct {
+		name      string
+		input     []string
+		wantCount uint
+		wantTotal uint
+	}{
+		{"not set", []string{"aaa", "/"}, 0, 0},
+		{"max count", []string{"-m", "2", "aaa", "/"}, 2, 0},
+		{"max count long", []string{"--max-count=3", "aaa", "/"}, 3, 0},
+		{"max total", []string{"--max-total", "4", "aaa", "/"}, 0, 4},
+		{"both", []string{"--max-total=5", "-m", "1", "aaa", "/"}, 1, 5},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			input := append([]string{"blush"}, tc.input...)
+			b, err := cmd.GetBlush(input)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.wantCount, b.MaxCount)
+			assert.Equal(t, tc.wantTotal, b.MaxTotal)
+			assert.True(t, argsEqual(b.Finders, []blush.Finder{blush.NewExact("aaa", blush.DefaultColour)}))
+		})
+	}
+
+	for _, input := range [][]string{
+		{"blush", "-m", "x", "aaa", "/"},
+		{"blush", "--max-total=-1", "aaa", "/"},
+	} {
+		b, err := cmd.GetBlush(input)
+		assert.True(t, errors.Is(err, cmd.ErrInvalidValue))
+		assert.Nil(t, b)
+	}
+}
+
+func TestMainMaxCount(t *testing.T) {
+	dir := t.TempDir()
+	file1 := path.Join(dir, "one.txt")
+	file2 := path.Join(dir, "two.txt")
+	err := os.WriteFile(file1, []byte("aaa 1\nbbb\naaa 2\naaa 3\n"), 0o600)
+	assert.NoError(t, err)
+	err = os.WriteFile(file2, []byte("aaa 4\naaa 5\n"), 0o600)
+	assert.NoError(t, err)
+
+	stdout, stderr := setup(t, fmt.Sprintf("--colour=never -h -d -m 2 aaa %s %s", file1, file2))
+	assert.Equal(t, cmd.ExitMatch, cmd.Main())
+	assert.Empty(t, stderr.String())
+	assert.Equal(t, "aaa 1\naaa 2\naaa 4\naaa 5\n", stdout.String())
+
+	stdout, stderr = setup(t, fmt.Sprintf("--colour=never -h -d --max-total=3 aaa %s %s", file1, file2))
+	assert.Equal(t, cmd.ExitMatch, cmd.Main())
+	assert.Empty(t, stderr.String())
+	assert.Equal(t, "aaa 1\naaa 2\naaa 3\n", stdout.String())
+}
+
+func TestBinaryFilesArgs(t *testing.T) {
+	tcs := []struct {
+		name  string
+		input []string
+		want  blush.BinaryMode
+	}{
+		{"not set", []string{"aaa", "/"}, blush.BinaryMatch},
+		{"match", []string{"--binary-files=match", "aaa", "/"}, blush.BinaryMatch},
+		{"skip", []string{"--binary-files", "skip", "aaa", "/"}, blush.BinarySkip},
+		{"text", []string{"--binary-files=text", "aaa", "/"}, blush.BinaryText},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			input := append([]string{"blush"}, tc.input...)
+			b, err := cmd.GetBlush(input)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, b.BinaryFiles)
+		})
+	}
+
+	b, err := cmd.GetBlush([]string{"blush", "--binary-files=binary", "aaa", "/"})
+	assert.True(t, errors.Is(err, cmd.ErrInvalidValue))
+	assert.Nil(t, b)
+}
+
+func TestMainBinaryFiles(t *testing.T) {
+	dir := t.TempDir()
+	binary := path.Join(dir, "binary")
+	text := path.Join(dir, "text.txt")
+	err := os.WriteFile(binary, []byte("aaa\x00\nbbb\n"), 0o600)
+	assert.NoError(t, err)
+	err = os.WriteFile(text, []byte("café aaa\n"), 0o600)
+	assert.NoError(t, err)
+
+	tcs := []struct {
+		name string
+		args string
+		want string
+	}{
+		{"default", "aaa", fmt.Sprintf("Binary file %s matches\n%s: café aaa\n", binary, text)},
+		{"skip", "--binary-files=skip aaa", fmt.Sprintf("%s: café aaa\n", text)},
+		{"text", "--binary-files=text aaa", fmt.Sprintf("%s: aaa\x00\n%s: café aaa\n", binary, text)},
+		{"no match", "bbb", fmt.Sprintf("Binary file %s matches\n", binary)},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			stdout, stderr := setup(t, fmt.Sprintf("--colour=never -d -R %s %s", tc.args, dir))
+			assert.Equal(t, cmd.ExitMatch, cmd.Main())
+			assert.Empty(t, stderr.String())
+			assert.Equal(t, tc.want, stdout.String())
+		})
+	}
+}
+
+func TestEncodingArgs(t *testing.T) {
+	for _, input := range [][]string{
+		{"blush", "--encoding=ebcdic", "aaa", "/"},
+		{"blush", "--output-encoding", "ebcdic", "aaa", "/"},
+	} {
+		b, err := cmd.GetBlush(input)
+		assert.True(t, errors.Is(err, cmd.ErrInvalidValue))
+		assert.Nil(t, b)
+	}
+	b, err := cmd.GetBlush([]string{"blush", "--encoding=latin-1", "--output-encoding", "utf-16le", "aaa", "/"})
+	assert.NoError(t, err)
+	assert.True(t, argsEqual(b.Finders, []blush.Finder{blush.NewExact("aaa", blush.DefaultColour)}))
+}
+
+func TestMainEncoding(t *testing.T) {
+	dir := t.TempDir()
+	utf16 := path.Join(dir, "utf16.txt")
+	cp1252 := path.Join(dir, "cp1252.txt")
+	err := os.WriteFile(utf16, []byte("\xff\xfec\x00a\x00f\x00\xe9\x00\n\x00"), 0o600)
+	assert.NoError(t, err)
+	err = os.WriteFile(cp1252, []byte("\x93caf\xe9\x94\n"), 0o600)
+	assert.NoError(t, err)
+
+	tcs := []struct {
+		name string
+		args string
+		want string
+	}{
+		{"auto", fmt.Sprintf("café %s", utf16), "café\n"},
+		{"windows-1252", fmt.Sprintf("--encoding=windows-1252 café %s", cp1252), "“café”\n"},
+		{"output", fmt.Sprintf("--output-encoding=latin-1 café %s", utf16), "caf\xe9\n"},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			stdout, stderr := setup(t, "--colour=never -h -d "+tc.args)
+			assert.Equal(t, cmd.ExitMatch, cmd.Main())
+			assert.Empty(t, stderr.String())
+			assert.Equal(t, tc.want, stdout.String())
+		})
+	}
+}
+
+func TestMainCompressed(t *testing.T) {
+	dir := t.TempDir()
+	plain := path.Join(dir, "app.log")
+	rotated := path.Join(dir, "app.log.1.gz")
+	err := os.WriteFile(plain, []byte("aaa\nERROR two\n"), 0o600)
+	assert.NoError(t, err)
+	buf := &bytes.Buffer{}
+	w := gzip.NewWriter(buf)
+	_, err = w.Write([]byte("ERROR one\nbbb\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	err = os.WriteFile(rotated, buf.Bytes(), 0o600)
+	assert.NoError(t, err)
+
+	stdout, stderr := setup(t, fmt.Sprintf("--colour=never -d -R -r ERROR %s", dir))
+	assert.Equal(t, cmd.ExitMatch, cmd.Main())
+	assert.Empty(t, stderr.String())
+	assert.Equal(t, fmt.Sprintf("%s: ERROR two\n%s: ERROR one\n", plain, rotated), stdout.String())
+}
+
+func TestMainArchives(t *testing.T) {
+	dir := t.TempDir()
+	bundle := path.Join(dir, "bundle.zip")
+	buf := &bytes.Buffer{}
+	w := zip.NewWriter(buf)
+	for _, m := range []struct{ name, content string }{
+		{"logs/app.log", "aaa\nERROR one\n"},
+		{"logs/core", "ERROR\x00\n"},
+		{"logs/db.log", "ERROR two\nbbb\n"},
+	} {
+		f, err := w.Create(m.name)
+		assert.NoError(t, err)
+		_, err = f.Write([]byte(m.content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+	err := os.WriteFile(bundle, buf.Bytes(), 0o600)
+	assert.NoError(t, err)
+
+	stdout, stderr := setup(t, fmt.Sprintf("--colour=never -d -r ERROR %s", bundle))
+	assert.Equal(t, cmd.ExitMatch, cmd.Main())
+	assert.Empty(t, stderr.String())
+	want := fmt.Sprintf("%[1]s!/logs/app.log: ERROR one\nBinary file %[1]s!/logs/core matches\n%[1]s!/logs/db.log: ERROR two\n", bundle)
+	assert.Equal(t, want, stdout.String())
+}
+
+func TestFollowArgs(t *testing.T) {
+	for _, arg := range []string{"-c", "-l", "-L"} {
+		_, err := cmd.GetBlush([]string{"blush", "-f", arg, "aaa", "/"})
+		assert.True(t, errors.Is(err, cmd.ErrConflictingArgs), arg)
+	}
+	for _, arg := range []string{"-f", "--follow"} {
+		b, err := cmd.GetBlush([]string{"blush", arg, "aaa", "/"})
+		assert.NoError(t, err)
+		m, ok := b.Reader.(*reader.MultiReader)
+		assert.True(t, ok)
+		assert.True(t, m.Following(), arg)
+	}
+}
+
+func TestMainFollow(t *testing.T) {
+	dir := t.TempDir()
+	file := path.Join(dir, "app.log")
+	err := os.WriteFile(file, []byte("aaa\nERROR one\n"), 0o600)
+	assert.NoError(t, err)
+
+	stdout, stderr := setup(t, fmt.Sprintf("--colour=never -d -n -f --max-total=2 ERROR %s", file))
+	code := make(chan int)
+	go func() {
+		code <- cmd.Main()
+	}()
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_WRONLY, 0o600)
+	assert.NoError(t, err)
+	_, err = f.WriteString("bbb\nERROR two\nccc\n")
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	select {
+	case c := <-code:
+		assert.Equal(t, cmd.ExitMatch, c)
+	case <-time.After(5 * time.Second):
+		t.Fatal("blush didn't stop")
+	}
+	assert.Empty(t, stderr.String())
+	assert.Equal(t, fmt.Sprintf("%[1]s:2: ERROR one\n%[1]s:4: ERROR two\n", file), stdout.String())
+}
+
+func TestTailArgs(t *testing.T) {
+	for _, input := range [][]string{
+		{"blush", "--tail", "x", "aaa", "/"},
+		{"blush", "--tail=-1", "aaa", "/"},
+	} {
+		b, err := cmd.GetBlush(input)
+		assert.True(t, errors.Is(err, cmd.ErrInvalidValue))
+		assert.Nil(t, b)
+	}
+	_, err := cmd.GetBlush([]string{"blush", "-n", "--tail", "2", "aaa", "/"})
+	assert.True(t, errors.Is(err, cmd.ErrConflictingArgs))
+	for _, input := range [][]string{
+		{"blush", "--tail", "2", "aaa", "/"},
+		{"blush", "--tail=2", "aaa", "/"},
+	} {
+		b, err := cmd.GetBlush(input)
+		assert.NoError(t, err)
+		assert.True(t, argsEqual(b.Finders, []blush.Finder{blush.NewExact("aaa", blush.DefaultColour)}))
+	}
+}
+
+func TestMainTail(t *testing.T) {
+	dir := t.TempDir()
+	file1 := path.Join(dir, "one.txt")
+	file2 := path.Join(dir, "two.txt")
+	err := os.WriteFile(file1, []byte("aaa 1\nbbb\naaa 2\naaa 3\n"), 0o600)
+	assert.NoError(t, err)
+	err = os.WriteFile(file2, []byte("aaa 4\n"), 0o600)
+	assert.NoError(t, err)
+
+	stdout, stderr := setup(t, fmt.Sprintf("--colour=never -d --tail 2 aaa %s %s", file1, file2))
+	assert.Equal(t, cmd.ExitMatch, cmd.Main())
+	assert.Empty(t, stderr.String())
+	assert.Equal(t, fmt.Sprintf("%[1]s: aaa 2\n%[1]s: aaa 3\n%[2]s: aaa 4\n", file1, file2), stdout.String())
+
+	stdout, stderr = setup(t, fmt.Sprintf("--colour=never -d --byte-offset --tail 2 aaa %s", file1))
+	assert.Equal(t, cmd.ExitMatch, cmd.Main())
+	assert.Empty(t, stderr.String())
+	assert.Equal(t, fmt.Sprintf("%[1]s:10: aaa 2\n%[1]s:16: aaa 3\n", file1), stdout.String())
+}
+
+func TestMainIgnore(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		".gitignore":        "vendor/\n",
+		"main.go":           "ERROR main\n",
+		"vendor/lib.go":     "ERROR vendor\n",
+		".hidden/config.go": "ERROR hidden\n",
+	} {
+		p := path.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(path.Dir(p), 0o700))
+		assert.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+	}
+	tcs := []struct {
+		name string
+		args string
+		want []string
+	}{
+		{"default", "", []string{"main.go: ERROR main"}},
+		{"no ignore", "--no-ignore", []string{"main.go: ERROR main", "vendor/lib.go: ERROR vendor"}},
+		{"hidden", "--hidden", []string{".gitignore: vendor/", ".hidden/config.go: ERROR hidden", "main.go: ERROR main"}},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			stdout, stderr := setup(t, fmt.Sprintf("--colour=never -d -R %s -r ERROR|vendor %s", tc.args, dir))
+			assert.Equal(t, cmd.ExitMatch, cmd.Main())
+			assert.Empty(t, stderr.String())
+			var want strings.Builder
+			for _, line := range tc.want {
+				want.WriteString(dir + "/" + line + "\n")
+			}
+			assert.Equal(t, want.String(), stdout.String())
+		})
+	}
+}
+
+func TestFileFilterArgs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"main.go", "index.html"} {
+		err := os.WriteFile(path.Join(dir, name), []byte("aaa\n"), 0o600)
+		assert.NoError(t, err)
+	}
+	for _, input := range [][]string{
+		{"blush", "-t", "nope", "aaa", dir},
+		{"blush", "--type=nope", "aaa", dir},
+		{"blush", "--type-add", "web", "aaa", dir},
+		{"blush", "--type-add=:*.html", "aaa", dir},
+		{"blush", "--type-add=web:", "aaa", dir},
+	} {
+		b, err := cmd.GetBlush(input)
+		assert.True(t, errors.Is(err, cmd.ErrInvalidValue), input)
+		assert.Nil(t, b)
+	}
+	for _, input := range [][]string{
+		{"blush", "-R", "--include", "*.go", "--exclude=*_test.go", "--exclude-dir", "vendor", "aaa", dir},
+		{"blush", "-R", "--type-add=web:*.html,*.css", "-t", "web", "--type=go", "aaa", dir},
+	} {
+		b, err := cmd.GetBlush(input)
+		assert.NoError(t, err)
+		assert.True(t, argsEqual(b.Finders, []blush.Finder{blush.NewExact("aaa", blush.DefaultColour)}))
+	}
+}
+
+func TestMainFileFilters(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"main.go":        "ERROR main\n",
+		"main_test.go":   "ERROR test\n",
+		"index.html":     "ERROR html\n",
+		"app.log":        "ERROR log\n",
+		"vendor/lib.go":  "ERROR vendor\n",
+		"docs/style.css": "ERROR css\n",
+	} {
+		p := path.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(path.Dir(p), 0o700))
+		assert.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+	}
+	tcs := []struct {
+		name string
+		args string
+		want []string
+	}{
+		{"include", "--include *.log", []string{"app.log: ERROR log"}},
+		{"exclude", "--exclude *.go --exclude=*.log", []string{"docs/style.css: ERROR css", "index.html: ERROR html"}},
+		{"exclude dir", "-t go --exclude-dir vendor", []string{"main.go: ERROR main", "main_test.go: ERROR test"}},
+		{"type", "-t go --exclude *_test.go", []string{"main.go: ERROR main", "vendor/lib.go: ERROR vendor"}},
+		{"type add", "--type-add web:*.html,*.css -t web", []string{"docs/style.css: ERROR css", "index.html: ERROR html"}},
+		{"path", "--include docs/*", []string{"docs/style.css: ERROR css"}},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			stdout, stderr := setup(t, fmt.Sprintf("--colour=never -d -R %s ERROR %s", tc.args, dir))
+			assert.Equal(t, cmd.ExitMatch, cmd.Main())
+			assert.Empty(t, stderr.String())
+			var want strings.Builder
+			for _, line := range tc.want {
+				want.WriteString(dir + "/" + line + "\n")
+			}
+			assert.Equal(t, want.String(), stdout.String())
+		})
+	}
+}
@@ -16,10 +16,45 @@ Colours:
     -#RGB, --#RGB   Use user defined colour schemas.
                     Example: blush -#1eF match filename
     -#RRGGBB, --#RRGGBB Same as -#RGB/--#RGB.
+    -yl/bl, --yellow/black  Use the second colour as the background. Any
+                        colour can be used on each side of the slash.
+                        Example: blush -#ff0/#333 match filename
+    --fg=COLOUR --bg=COLOUR Set the foreground and background colours
+                        separately. They are combined until the next colour
+                        argument.
+                        Example: blush --fg=yl --bg=#333 match filename
+    -r,ATTR[,ATTR]...   Add text attributes to the colour. They can be
+                        separated with commas or plus signs.
+                        Example: blush --red,bold,underline match filename
+                        Example: blush -g+u match filename
+
+Colour output:
+    --colour[=WHEN]         Colour the matches. WHEN can be auto, always or
+                            never. With auto, which is the default, the output
+                            is coloured only if it goes to a terminal. The
+                            NO_COLOR and CLICOLOR_FORCE environment variables
+                            turn the colours off and on in this mode.
+
+Colour depth:
+    The number of colours of the terminal is detected from the COLORTERM and
+    TERM environment variables. With 16 colours, the closest colour of the
+    palette is chosen, and with truecolor all colours are shown as they are.
+    --colour-depth=DEPTH    Override the detection. DEPTH can be 16, 256 or
+                            truecolor.
 
 Pattern:
     You can use simple pattern or regexp. If your pattern expands between
     multiple words or has space in between, you should put them in quotations.
+    A pattern is taken as a regexp if it has any of the ^$.{}[]*?|()
+    characters, otherwise it is searched as it is.
+    -F, --fixed-strings     Search all patterns as they are.
+    -E, --extended-regexp   Take all patterns as regexps.
+    -e PATTERN, --regexp=PATTERN
+                            Take PATTERN as a regexp. It can start with a dash.
+                            Example: blush -r -e -foo filename
+    --                      Take all the following arguments, except the
+                            files, as patterns even if they start with a dash.
+                            Example: blush -r -- -foo --bar filename
 
 Stock Colours:
     -r, --red
@@ -31,15 +66,145 @@ Stock Colours:
     -mg, --magenta
     -cy, --cyan
 
+Text Attributes:
+    bold, b             Bold text.
+    dim, d              Faint text.
+    italic, i           Italic text.
+    underline, u        Underlined text.
+    reverse, r          Swap the foreground and background colours.
+    strikethrough, s    Crossed out text.
+
 Control arguments:
     -d, --drop              Drop unmatched lines.
+    -v, --invert-match      Only print the lines that none of the patterns
+                            match.
+    --not PATTERN, --not=PATTERN
+                            Drop the lines that match PATTERN, even if other
+                            patterns match them. It can be repeated.
+                            Example: blush -b GET --not health filename
+    -A NUM, --after-context=NUM
+                            Print NUM lines after each matched line.
+    -B NUM, --before-context=NUM
+                            Print NUM lines before each matched line.
+    -C NUM, --context=NUM   Print NUM lines before and after each matched line.
+                            The context lines are dimmed, and "--" is printed
+                            between the groups of lines that are not next to
+                            each other.
     -i                      Case insensitive match.
+    -R                      Search the files in the directories recursively.
+                            The files and directories that match the patterns
+                            of the .gitignore, .ignore and .blushignore files
+                            in the directories, the hidden ones, and the .git
+                            directories are skipped. In a git repository, the
+                            ignore files of the parent directories up to its
+                            root are read too.
+    --no-ignore             Don't skip the files that match the patterns of
+                            the ignore files.
+    --hidden                Search the files and directories that start with
+                            a dot. The .git directories are only searched with
+                            --no-ignore.
+    --include GLOB, --include=GLOB
+                            Only search the files that match GLOB in the
+                            directories. It can be repeated.
+                            Example: blush -R --include '*.log' ERROR .
+    --exclude GLOB, --exclude=GLOB
+                            Skip the files that match GLOB in the directories.
+                            It can be repeated.
+    --exclude-dir GLOB, --exclude-dir=GLOB
+                            Skip the directories that match GLOB. It can be
+                            repeated.
+    -t TYPE, --type=TYPE    Only search the files of TYPE in the directories.
+                            It can be repeated. TYPE can be c, cpp, csharp,
+                            css, csv, docker, go, html, ini, java, js, json,
+                            kotlin, log, lua, make, markdown, md, php, proto,
+                            py, ruby, rust, sh, sql, swift, toml, ts, txt, xml
+                            or yaml.
+    --type-add NAME:GLOB[,GLOB]...
+                            Add the globs to the NAME type, which can be a
+                            new type.
+                            Example: blush -R --type-add web:*.html,*.css -t web
+                            The globs match the names of the files and the
+                            directories, or their paths in the searched
+                            directory if they have a slash. A "*" doesn't
+                            match a slash and "**" matches any directories.
     -h, --no-filename       Suppress the prefixing of file names on output.
+    -n, --line-number       Prefix each line with its line number in its file.
+    --byte-offset           Prefix each line with the byte offset of its start
+                            in its file. There is no short form because -b is
+                            the blue colour.
+    -o, --only-matching     Only print the matched parts of the lines, each on
+                            its own line.
+    -c, --count             Only print the number of matched lines of each
+                            file.
+    --stats                 Print the number of matches and matched lines of
+                            each pattern, and the number of files, lines and
+                            bytes that are read to the standard error.
+    -m NUM, --max-count=NUM Stop reading a file after NUM selected lines. The
+                            after context lines of the last one are printed.
+    --max-total NUM, --max-total=NUM
+                            Stop reading after NUM selected lines in all files.
+    --binary-files=TYPE     Decide how to read the binary files. TYPE can be
+                            match, skip or text. With match, which is the
+                            default, "Binary file FILE matches" is printed
+                            instead of the lines of a binary file. With skip
+                            they are not read, and with text they are read as
+                            if they were text files. A file is binary if it
+                            has NUL bytes or too many invalid UTF-8 bytes.
+    --encoding=NAME         Decode the input from NAME before searching it. NAME
+                            can be auto, utf-8, utf-16le, utf-16be, latin-1 or
+                            windows-1252. With auto, which is the default, the
+                            files that start with a UTF-16 byte order mark are
+                            decoded as UTF-16 and the rest as UTF-8.
+    --output-encoding=NAME  Encode the output to NAME. The output is UTF-8 by
+                            default.
+    -l, --files-with-matches
+                            Only print the names of the files that have any
+                            selected lines. Each file is read up to its first
+                            selected line.
+    -L, --files-without-match
+                            Only print the names of the files that don't have
+                            any selected lines.
+    -q, --quiet             Don't print anything, and stop at the first
+                            selected line. Use the exit status to find out if
+                            there is any.
+    -f, --follow            Keep reading the files after they are read, like
+                            tail -F, and print the new lines as they are
+                            written. The files are opened again when they are
+                            rotated, and read from the start when they are
+                            truncated. A line is printed when its newline is
+                            written, and -m limits the lines of each file,
+                            including the new ones. It can't be used with -c,
+                            -l and -L.
+    --tail NUM, --tail=NUM  Only read the last NUM lines of each file. The
+                            files are read backwards from their end to find
+                            them, but the compressed files and the input of
+                            pipes are read to the end. The byte offsets are
+                            counted from the start of the files. It can't be
+                            used with -n, because the lines before the last
+                            ones are not counted. With -f, the files are
+                            followed after their last lines are printed.
 
 Multi match colouring:
     blush -b match1 [match2]...: will colourise all matches with the same colour.
 
 Using pipes:
     cat FILE | blush -b match [-g match]...
+
+Compressed files:
+    The files that are compressed with gzip, bzip2 or zlib are decompressed
+    when they are read, and are shown with their own names. The format is found
+    from the contents of the files, not their names. Zstandard files are not
+    supported.
+
+Archives:
+    Each file in a zip or a tar archive is searched separately, and is shown
+    with the name of the archive and its path in it, for example
+    bundle.zip!/logs/app.log. The tar archives can be compressed, and the files
+    in the archives are decompressed too. Binary files are found for each file.
+
+Exit status:
+    The exit status is 0 if any lines are selected, 1 if no lines are selected,
+    and 2 if an error has occurred. With -v, the selected lines are the ones
+    that none of the patterns match.
 `
 )
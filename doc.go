@@ -9,7 +9,9 @@
 // Colouring Method
 //
 // With this method all texts are shown, but the matching words are coloured.
-// You can activate this mode by providing "--colour" or "-C" argument.
+// The output is coloured only if it goes to a terminal. You can change this
+// with the "--colour=always" or "--colour=never" arguments. The NO_COLOR and
+// CLICOLOR_FORCE environment variables are also honoured.
 //
 // Piping
 //
@@ -19,15 +21,47 @@
 //
 // Arguments
 //
-//  +---------------+----------+------------------------------------------------+
-//  |    Argument   | Shortcut |                     Notes                      |
-//  +---------------+----------+------------------------------------------------+
-//  | --colour      | -C       | Colour, don't drop anything.                   |
-//  | N/A           | -i       | Case insensitive matching                      |
-//  | N/A           | -R       | Recursive                                      |
-//  | --no-colour   | N/A      | Doesn't colourize matches.                     |
-//  | --no-filename | -h       | Suppress the prefixing of file names on output |
-//  +---------------+----------+------------------------------------------------+
+//  +------------------------+------------+------------------------------------------------+
+//  |        Argument        |  Shortcut  |                     Notes                      |
+//  +------------------------+------------+------------------------------------------------+
+//  | --colour=WHEN          | N/A        | auto, always or never. Default is auto.        |
+//  | N/A                    | -i         | Case insensitive matching                      |
+//  | N/A                    | -R         | Recursive                                      |
+//  | --no-colour            | N/A        | Doesn't colourize matches.                     |
+//  | --no-filename          | -h         | Suppress the prefixing of file names on output |
+//  | --colour-depth         | N/A        | 16, 256 or truecolor. Detected by default.     |
+//  | --fixed-strings        | -F         | Search all patterns as they are.               |
+//  | --extended-regexp      | -E         | Take all patterns as regexps.                  |
+//  | --regexp=PATTERN       | -e PATTERN | Take PATTERN as a regexp.                      |
+//  | N/A                    | --         | The following patterns can start with a dash.  |
+//  | --invert-match         | -v         | Only print the lines that do not match.        |
+//  | --not=PATTERN          | N/A        | Drop the lines that match PATTERN.             |
+//  | --after-context=N      | -A N       | Print N lines after each match.                |
+//  | --before-context=N     | -B N       | Print N lines before each match.               |
+//  | --context=N            | -C N       | Print N lines around each match.               |
+//  | --line-number          | -n         | Prefix the lines with their line numbers.      |
+//  | --byte-offset          | N/A        | Prefix the lines with their byte offsets.      |
+//  | --only-matching        | -o         | Only print the matched parts.                  |
+//  | --count                | -c         | Print the number of matched lines.             |
+//  | --stats                | N/A        | Print the statistics to stderr.                |
+//  | --quiet                | -q         | Print nothing, stop at the first match.        |
+//  | --files-with-matches   | -l         | Only print the files with matches.             |
+//  | --files-without-match  | -L         | Only print the files without matches.          |
+//  | --max-count=N          | -m N       | Stop reading a file after N matched lines.     |
+//  | --max-total=N          | N/A        | Stop reading after N matched lines.            |
+//  | --binary-files=TYPE    | N/A        | match, skip or text. Default is match.         |
+//  | --encoding=NAME        | N/A        | Encoding of the input. Default is auto.        |
+//  | --output-encoding=NAME | N/A        | Encoding of the output. Default is utf-8.      |
+//  | --follow               | -f         | Keep reading the files as they grow.           |
+//  | --tail=N               | N/A        | Only read the last N lines of each file.       |
+//  | --no-ignore            | N/A        | Search the ignored files.                      |
+//  | --hidden               | N/A        | Search the hidden files.                       |
+//  | --include=GLOB         | N/A        | Only search the files that match GLOB.         |
+//  | --exclude=GLOB         | N/A        | Skip the files that match GLOB.                |
+//  | --exclude-dir=GLOB     | N/A        | Skip the directories that match GLOB.          |
+//  | --type=TYPE            | -t TYPE    | Only search the files of TYPE, like go.        |
+//  | --type-add=NAME:GLOB   | N/A        | Add GLOB to the NAME file type.                |
+//  +------------------------+------------+------------------------------------------------+
 //
 // File names or paths are matched from the end. Any argument that doesn't match
 // any files or paths are considered as regular expression. If regular
@@ -41,6 +75,8 @@
 // If no colour is provided, blush will choose blue. If you only provide
 // file/path, it will print them out without colouring. If the matcher contains
 // only alphabets and numbers, a non-regular expression is applied to search.
+// Unknown colours or attributes, and patterns that look like regular
+// expressions but don't compile, are reported as errors.
 //
 // Colour Groups
 //
@@ -74,6 +110,20 @@
 //  | --#11bb22 | --#1b2   |
 //  +-----------+----------+
 //
+// A background colour can be given after a slash, for example "-yl/bl" or
+// "--#ff0/#333". You can also set them separately with "--fg=COLOUR" and
+// "--bg=COLOUR", which are combined until the next colour argument:
+//
+//  $ blush -yl/bl ERROR --fg=yl --bg=#333 WARN FILENAME
+//
+// Text Attributes
+//
+// You can add bold, dim, italic, underline, reverse and strikethrough
+// attributes to a colour. They can be separated with commas or plus signs, and
+// their first letter can be used as their short form:
+//
+//  $ blush --red,bold,underline ERROR -g+u WARN FILENAME
+//
 // Complex Grep
 //
 // You must put your complex grep into quotations:
@@ -0,0 +1,203 @@
+package reader
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"path"
+
+	"github.com/pkg/errors"
+)
+
+// These are the magic bytes of the archives. The tar magic is at tarMagicAt in
+// the header of the first member.
+var (
+	magicZip = []byte("PK\x03\x04")
+	magicTar = []byte("ustar")
+)
+
+const (
+	tarMagicAt    = 257
+	tarHeaderSize = 512
+)
+
+// archive reads the members of a zip or a tar file one by one. Each member is
+// named after the archive and its path inside the archive, for example
+// "bundle.zip!/logs/app.log".
+type archive struct {
+	name     string
+	encoding Encoding
+	tail     uint // number of the last lines of each member to read.
+	// next returns the next regular file in the archive. It returns io.EOF when
+	// there are no more files.
+	next   func() (string, io.ReadCloser, error)
+	closer io.Closer
+	cur    io.ReadCloser // member that is being read by Read.
+	done   bool
+}
+
+// openFile opens the file and returns its contents. The compressed files are
+// decompressed, and if the file is a zip or a tar archive an *archive is
+// returned. If the MultiReader follows the files and the file is a plain file,
+// it is added to the followed files. If only the tail of the file is needed,
+// the start of its tail is found from the end of the file when possible.
+func (m *MultiReader) openFile(name string) (io.ReadCloser, error) {
+	f, err := os.Open(name) // nolint:gosec // we need this.
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close() // nolint:errcheck,gosec // the other error is more important.
+		return nil, err
+	}
+	head := make([]byte, sniffSize)
+	n, _ := f.ReadAt(head, 0) // nolint:errcheck // the file can be shorter.
+	head = head[:n]
+	if bytes.HasPrefix(head, magicZip) {
+		a, err := openZip(name, f, info.Size())
+		if err != nil {
+			f.Close() // nolint:errcheck,gosec // the other error is more important.
+			return nil, errors.Wrap(err, name)
+		}
+		a.encoding, a.tail = m.encoding, m.tail
+		return a, nil
+	}
+	plain := info.Mode().IsRegular() && !isCompressed(head) && !isTar(head)
+	if plain && m.follow != nil {
+		t := &tailed{name: name, f: f, info: info}
+		r, err := m.follow.open(t, head, m.encoding, m.tail)
+		if err != nil {
+			f.Close() // nolint:errcheck,gosec // the other error is more important.
+			return nil, errors.Wrap(err, name)
+		}
+		return r, nil
+	}
+	if plain && m.tail > 0 && !isUTF16(head, m.encoding) {
+		offset, err := tailOffset(f, info.Size(), m.tail)
+		if err == nil {
+			_, err = f.Seek(offset, io.SeekStart)
+		}
+		if err != nil {
+			f.Close() // nolint:errcheck,gosec // the other error is more important.
+			return nil, errors.Wrap(err, name)
+		}
+		return seeked{Decode(f, m.encoding), offset}, nil
+	}
+	r, err := decompress(f)
+	if err != nil {
+		return nil, errors.Wrap(err, name)
+	}
+	br := bufio.NewReaderSize(r, tarHeaderSize)
+	head = peek(br, tarHeaderSize)
+	if isTar(head) {
+		a := openTar(name, readCloser{br, r})
+		a.encoding, a.tail = m.encoding, m.tail
+		return a, nil
+	}
+	return Tail(Decode(readCloser{br, r}, m.encoding), m.tail), nil
+}
+
+func openZip(name string, f *os.File, size int64) (*archive, error) {
+	zr, err := zip.NewReader(f, size)
+	if err != nil {
+		return nil, err
+	}
+	files := zr.File
+	next := func() (string, io.ReadCloser, error) {
+		for len(files) > 0 {
+			zf := files[0]
+			files = files[1:]
+			if !zf.Mode().IsRegular() {
+				continue
+			}
+			r, err := zf.Open()
+			return zf.Name, r, err
+		}
+		return "", nil, io.EOF
+	}
+	return &archive{name: name, next: next, closer: f}, nil
+}
+
+func openTar(name string, r io.ReadCloser) *archive {
+	tr := tar.NewReader(r)
+	next := func() (string, io.ReadCloser, error) {
+		for {
+			hdr, err := tr.Next()
+			if err != nil {
+				return "", nil, err
+			}
+			if hdr.FileInfo().Mode().IsRegular() {
+				return hdr.Name, io.NopCloser(tr), nil
+			}
+		}
+	}
+	return &archive{name: name, next: next, closer: r}
+}
+
+// isTar reports whether the head is the beginning of a tar archive.
+func isTar(head []byte) bool {
+	return len(head) >= tarHeaderSize && bytes.HasPrefix(head[tarMagicAt:], magicTar)
+}
+
+// nextMember returns the name and the decompressed and decoded contents of the
+// next member. It returns io.EOF when there are no more members. If the
+// archive is broken, the error is returned and the rest of the archive is
+// skipped.
+func (a *archive) nextMember() (string, io.ReadCloser, error) {
+	if a.done {
+		return "", nil, io.EOF
+	}
+	member, r, err := a.next()
+	if err != nil {
+		a.done = true
+		if errors.Is(err, io.EOF) {
+			return "", nil, io.EOF
+		}
+		return "", nil, errors.Wrap(err, a.name)
+	}
+	name := a.name + "!" + path.Clean("/"+member)
+	r, err = decompress(r)
+	if err != nil {
+		return "", nil, errors.Wrap(err, name)
+	}
+	return name, Tail(Decode(r, a.encoding), a.tail), nil
+}
+
+// Read reads the contents of all members one after another.
+func (a *archive) Read(b []byte) (int, error) {
+	for {
+		if a.cur == nil {
+			_, r, err := a.nextMember()
+			if err != nil {
+				return 0, err
+			}
+			a.cur = r
+		}
+		n, err := a.cur.Read(b)
+		if errors.Is(err, io.EOF) {
+			err = a.cur.Close()
+			a.cur = nil
+			if n == 0 && err == nil {
+				continue
+			}
+		}
+		return n, err
+	}
+}
+
+// Close closes the member that is being read and the archive file.
+func (a *archive) Close() error {
+	var err error
+	if a.cur != nil {
+		err = a.cur.Close()
+		a.cur = nil
+	}
+	if e := a.closer.Close(); e != nil && err == nil {
+		err = e
+	}
+	return err
+}
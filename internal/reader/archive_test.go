@@ -0,0 +1,183 @@
+package reader_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/alecthomas/assert"
+	"github.com/arsham/blush/internal/reader"
+)
+
+type member struct {
+	name    string
+	content string
+}
+
+var archiveMembers = []member{
+	{"logs/", ""},
+	{"logs/app.log", "one\nERROR two\n"},
+	{"./logs/db.log", "three\n"},
+}
+
+func zipFile(t *testing.T, members []member) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	w := zip.NewWriter(buf)
+	for _, m := range members {
+		f, err := w.Create(m.name)
+		assert.NoError(t, err)
+		_, err = f.Write([]byte(m.content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func tarFile(t *testing.T, members []member, compress bool) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	var out io.WriteCloser = nopWriteCloser{buf}
+	if compress {
+		out = gzip.NewWriter(buf)
+	}
+	w := tar.NewWriter(out)
+	for _, m := range members {
+		hdr := &tar.Header{Name: m.name, Mode: 0o600, Size: int64(len(m.content))}
+		if m.name[len(m.name)-1] == '/' {
+			hdr.Typeflag = tar.TypeDir
+			hdr.Mode = 0o700
+		}
+		assert.NoError(t, w.WriteHeader(hdr))
+		_, err := w.Write([]byte(m.content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+	assert.NoError(t, out.Close())
+	return buf.Bytes()
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestWithPathsArchives(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name string
+		file func(*testing.T) []byte
+	}{
+		{"bundle.zip", func(t *testing.T) []byte { return zipFile(t, archiveMembers) }},
+		{"bundle.tar", func(t *testing.T) []byte { return tarFile(t, archiveMembers, false) }},
+		{"bundle.tar.gz", func(t *testing.T) []byte { return tarFile(t, archiveMembers, true) }},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			name := path.Join(dir, tc.name)
+			err := os.WriteFile(name, tc.file(t), 0o600)
+			assert.NoError(t, err)
+
+			m, err := reader.NewMultiReader(reader.WithPaths([]string{name}, false))
+			assert.NoError(t, err)
+			for _, want := range archiveMembers[1:] {
+				got, r, err := m.NextSource()
+				assert.NoError(t, err)
+				assert.Equal(t, name+"!/"+path.Clean(want.name), got)
+				assert.Equal(t, got, m.FileName())
+				b, err := io.ReadAll(r)
+				assert.NoError(t, err)
+				assert.Equal(t, want.content, string(b))
+			}
+			_, _, err = m.NextSource()
+			assert.Equal(t, io.EOF, err)
+			assert.NoError(t, m.Close())
+		})
+	}
+}
+
+func TestWithPathsArchiveMembersDecompress(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	name := path.Join(dir, "bundle.zip")
+	members := []member{{"app.log.1.gz", string(compressed(t, "gzip"))}}
+	err := os.WriteFile(name, zipFile(t, members), 0o600)
+	assert.NoError(t, err)
+
+	m, err := reader.NewMultiReader(reader.WithPaths([]string{name}, false))
+	assert.NoError(t, err)
+	got, r, err := m.NextSource()
+	assert.NoError(t, err)
+	assert.Equal(t, name+"!/app.log.1.gz", got)
+	b, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, compressContent, string(b))
+	assert.NoError(t, m.Close())
+}
+
+func TestWithPathsArchiveAndFiles(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	err := os.WriteFile(path.Join(dir, "a.log"), []byte("aaa\n"), 0o600)
+	assert.NoError(t, err)
+	err = os.WriteFile(path.Join(dir, "b.zip"), zipFile(t, archiveMembers), 0o600)
+	assert.NoError(t, err)
+	err = os.WriteFile(path.Join(dir, "c.log"), []byte("ccc\n"), 0o600)
+	assert.NoError(t, err)
+
+	m, err := reader.NewMultiReader(reader.WithPaths([]string{dir}, false))
+	assert.NoError(t, err)
+	want := []string{
+		path.Join(dir, "a.log"),
+		path.Join(dir, "b.zip") + "!/logs/app.log",
+		path.Join(dir, "b.zip") + "!/logs/db.log",
+		path.Join(dir, "c.log"),
+	}
+	for _, name := range want {
+		got, _, err := m.NextSource()
+		assert.NoError(t, err)
+		assert.Equal(t, name, got)
+	}
+	_, _, err = m.NextSource()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestWithPathsArchiveRead(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	name := path.Join(dir, "bundle.tar.gz")
+	err := os.WriteFile(name, tarFile(t, archiveMembers, true), 0o600)
+	assert.NoError(t, err)
+
+	m, err := reader.NewMultiReader(reader.WithPaths([]string{name}, false))
+	assert.NoError(t, err)
+	b, err := io.ReadAll(m)
+	assert.NoError(t, err)
+	assert.Equal(t, "one\nERROR two\nthree\n", string(b))
+}
+
+func TestWithPathsBrokenArchive(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	name := path.Join(dir, "bundle.zip")
+	err := os.WriteFile(name, []byte("PK\x03\x04 not really a zip file"), 0o600)
+	assert.NoError(t, err)
+	err = os.WriteFile(path.Join(dir, "other.log"), []byte("aaa\n"), 0o600)
+	assert.NoError(t, err)
+
+	m, err := reader.NewMultiReader(reader.WithPaths([]string{dir}, false))
+	assert.NoError(t, err)
+	_, _, err = m.NextSource()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), name)
+	got, _, err := m.NextSource()
+	assert.NoError(t, err)
+	assert.Equal(t, path.Join(dir, "other.log"), got)
+}
@@ -0,0 +1,101 @@
+package reader
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// These are the magic bytes at the beginning of the compressed files.
+var (
+	magicGzip  = []byte{0x1f, 0x8b}
+	magicBzip2 = []byte("BZh")
+	// The bzip2 header is followed by the magic of the first block, or the
+	// magic of the end of the stream if it is empty.
+	magicBzip2Block = []byte{0x31, 0x41, 0x59, 0x26, 0x53, 0x59}
+	magicBzip2End   = []byte{0x17, 0x72, 0x45, 0x38, 0x50, 0x90}
+)
+
+// sniffSize is the number of bytes that are peeked to find the compression
+// format of a file.
+const sniffSize = 512
+
+// readCloser reads from the Reader and closes the Closer.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// peek returns the next n bytes of br without reading them, or less if the
+// input is shorter. Peek returns an error with the bytes that it could get in
+// that case, which are still valid, therefore the error is dropped.
+func peek(br *bufio.Reader, n int) []byte {
+	b, _ := br.Peek(n) // nolint:errcheck // explained above.
+	return b
+}
+
+// decompress returns a reader that decompresses r if it is compressed with
+// gzip, bzip2 or zlib, otherwise it returns the contents of r as they are.
+// The format is found from the first bytes of r. Closing the returned reader
+// closes r. If r looks compressed but its header is broken, r is closed and an
+// error is returned.
+func decompress(r io.ReadCloser) (io.ReadCloser, error) {
+	var (
+		br   = bufio.NewReader(r)
+		dr   io.Reader
+		head = peek(br, sniffSize)
+		err  error
+	)
+	switch {
+	case bytes.HasPrefix(head, magicGzip):
+		dr, err = gzip.NewReader(br)
+	case isBzip2(head):
+		dr = bzip2.NewReader(br)
+	case isZlib(head):
+		dr, err = zlib.NewReader(br)
+	default:
+		dr = br
+	}
+	if err != nil {
+		r.Close() // nolint:errcheck,gosec // the other error is more important.
+		return nil, errors.Wrap(err, "decompress")
+	}
+	return readCloser{dr, r}, nil
+}
+
+// isCompressed reports whether the head is the beginning of a file that is
+// decompressed by decompress.
+func isCompressed(head []byte) bool {
+	return bytes.HasPrefix(head, magicGzip) || isBzip2(head) || isZlib(head)
+}
+
+// isBzip2 reports whether the head is the beginning of a bzip2 stream. The
+// "BZh" magic can be found in texts, therefore the block size digit and the
+// magic of the first block should follow it.
+func isBzip2(head []byte) bool {
+	if len(head) < 10 || !bytes.HasPrefix(head, magicBzip2) || head[3] < '1' || head[3] > '9' {
+		return false
+	}
+	return bytes.HasPrefix(head[4:], magicBzip2Block) || bytes.HasPrefix(head[4:], magicBzip2End)
+}
+
+// isZlib reports whether the head is the beginning of a zlib stream. The zlib
+// header is only two bytes and can be found in texts, such as "x^", therefore
+// the head should also be decompressed without errors. The stream can only be
+// cut off if the head is not the whole file.
+func isZlib(head []byte) bool {
+	zr, err := zlib.NewReader(bytes.NewReader(head))
+	if err != nil {
+		return false
+	}
+	_, err = zr.Read(make([]byte, 1))
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return len(head) == sniffSize
+	}
+	return err == nil || errors.Is(err, io.EOF)
+}
@@ -0,0 +1,110 @@
+package reader_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/hex"
+	"io"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/alecthomas/assert"
+	"github.com/arsham/blush/internal/reader"
+)
+
+const compressContent = "aaa\nERROR one\nbbb\n"
+
+// bzip2Content is compressContent compressed with bzip2, because the bzip2
+// package can't compress.
+const bzip2Content = "425a68393141592653591307f557000005d70000104000020090003201a00031003021a34c8626c861a82a06f778bb9229c28480983faab8"
+
+func compressed(t *testing.T, format string) []byte {
+	t.Helper()
+	buf := &bytes.Buffer{}
+	var w io.WriteCloser
+	switch format {
+	case "gzip":
+		w = gzip.NewWriter(buf)
+	case "zlib":
+		w = zlib.NewWriter(buf)
+	case "bzip2":
+		b, err := hex.DecodeString(bzip2Content)
+		assert.NoError(t, err)
+		return b
+	default:
+		return []byte(compressContent)
+	}
+	_, err := w.Write([]byte(compressContent))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestWithPathsDecompress(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	names := []string{"app.log", "app.log.1.gz", "app.log.2.bz2", "app.log.3.zz"}
+	formats := []string{"plain", "gzip", "bzip2", "zlib"}
+	for i, name := range names {
+		err := os.WriteFile(path.Join(dir, name), compressed(t, formats[i]), 0o600)
+		assert.NoError(t, err)
+	}
+
+	m, err := reader.NewMultiReader(reader.WithPaths([]string{dir}, false))
+	assert.NoError(t, err)
+	for _, name := range names {
+		got, r, err := m.NextSource()
+		assert.NoError(t, err)
+		assert.Equal(t, path.Join(dir, name), got)
+		b, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, compressContent, string(b), name)
+	}
+	_, _, err = m.NextSource()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestWithPathsDecompressNotCompressed(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name    string
+		content string
+	}{
+		{"zlib header", "x^ this looks like zlib\n"},
+		{"zlib header short", "x^"},
+		{"bzip2 letters", "BZ\n"},
+		{"bzip2 header", "BZh is a word\nfoo\n"},
+		{"bzip2 header with size", "BZh9 is a word\nfoo\n"},
+		{"empty", ""},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			name := path.Join(t.TempDir(), "file")
+			err := os.WriteFile(name, []byte(tc.content), 0o600)
+			assert.NoError(t, err)
+			m, err := reader.NewMultiReader(reader.WithPaths([]string{name}, false))
+			assert.NoError(t, err)
+			b, err := io.ReadAll(m)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.content, string(b))
+		})
+	}
+}
+
+func TestWithPathsDecompressErrors(t *testing.T) {
+	t.Parallel()
+	name := path.Join(t.TempDir(), "file.gz")
+	err := os.WriteFile(name, []byte{0x1f, 0x8b, 0x00}, 0o600)
+	assert.NoError(t, err)
+	m, err := reader.NewMultiReader(reader.WithPaths([]string{name}, false))
+	assert.NoError(t, err)
+	_, _, err = m.NextSource()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), name)
+
+	_, _, err = m.NextSource()
+	assert.Equal(t, io.EOF, err)
+}
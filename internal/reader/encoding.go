@@ -0,0 +1,305 @@
+package reader
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// ErrUnknownEncoding is returned if the name of an encoding is not recognised.
+var ErrUnknownEncoding = errors.New("unknown encoding")
+
+// Encoding is the character encoding of a source. The sources are decoded to
+// UTF-8 before they are searched.
+type Encoding int
+
+const (
+	// Auto decodes the sources that start with a UTF-16 byte order mark as
+	// UTF-16, and the rest as UTF-8. The UTF-8 byte order mark is dropped.
+	Auto Encoding = iota
+	// UTF8 leaves the sources as they are, apart from their byte order mark.
+	UTF8
+	// UTF16LE is the little endian UTF-16.
+	UTF16LE
+	// UTF16BE is the big endian UTF-16.
+	UTF16BE
+	// Latin1 is the ISO-8859-1 encoding.
+	Latin1
+	// Windows1252 is the Windows Western European code page, which is Latin1
+	// with printable characters in place of the 0x80-0x9F control characters.
+	Windows1252
+)
+
+// These are the byte order marks that are detected in the Auto encoding.
+const (
+	bomUTF8    = "\xef\xbb\xbf"
+	bomUTF16LE = "\xff\xfe"
+	bomUTF16BE = "\xfe\xff"
+)
+
+// chunkSize is the number of bytes a decoder reads from its source at a time.
+const chunkSize = 4096
+
+// windows1252 holds the runes of the 0x80-0x9F bytes in Windows1252. The bytes
+// that are not defined are mapped to the same control characters as Latin1.
+var windows1252 = [32]rune{
+	'€', '\u0081', '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', '\u008d', 'Ž', '\u008f',
+	'\u0090', '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', '\u009d', 'ž', 'Ÿ',
+}
+
+// ParseEncoding returns the Encoding of the name. The name is case
+// insensitive and can be auto, utf-8, utf-16le, utf-16be, latin-1 (or
+// iso-8859-1) and windows-1252 (or cp1252).
+func ParseEncoding(name string) (Encoding, error) {
+	switch strings.ToLower(name) {
+	case "auto":
+		return Auto, nil
+	case "utf-8", "utf8":
+		return UTF8, nil
+	case "utf-16le", "utf16le":
+		return UTF16LE, nil
+	case "utf-16be", "utf16be":
+		return UTF16BE, nil
+	case "latin-1", "latin1", "iso-8859-1":
+		return Latin1, nil
+	case "windows-1252", "cp1252":
+		return Windows1252, nil
+	}
+	return Auto, errors.Wrapf(ErrUnknownEncoding, "ParseEncoding(%q)", name)
+}
+
+// WithEncoding decodes all the sources of the MultiReader from the encoding.
+// The sources are decoded when they are opened, therefore it can be given
+// before or after the other Confs.
+func WithEncoding(e Encoding) Conf {
+	return func(m *MultiReader) error {
+		m.encoding = e
+		return nil
+	}
+}
+
+// Decode returns a ReadCloser that decodes r from the encoding to UTF-8.
+// Closing it closes r.
+func Decode(r io.ReadCloser, e Encoding) io.ReadCloser {
+	return readCloser{NewDecoder(r, e), r}
+}
+
+// NewDecoder returns a reader that decodes r from the encoding to UTF-8. The
+// byte order mark of the encoding is dropped if r starts with it. Any invalid
+// bytes are replaced with utf8.RuneError.
+func NewDecoder(r io.Reader, e Encoding) io.Reader {
+	br := bufio.NewReader(r)
+	head := peek(br, len(bomUTF8))
+	e = detectEncoding(head, e)
+	switch {
+	case e == UTF8 && bytes.HasPrefix(head, []byte(bomUTF8)),
+		e == UTF16LE && bytes.HasPrefix(head, []byte(bomUTF16LE)),
+		e == UTF16BE && bytes.HasPrefix(head, []byte(bomUTF16BE)):
+		br.Discard(bom(e)) // nolint:errcheck,gosec // the bytes are peeked.
+	}
+	d := &decoder{r: br}
+	switch e {
+	case UTF8:
+		return br
+	case UTF16LE:
+		d.decode = decodeUTF16(binary.LittleEndian)
+	case UTF16BE:
+		d.decode = decodeUTF16(binary.BigEndian)
+	case Latin1:
+		d.decode = decodeLatin1
+	case Windows1252:
+		d.decode = decodeWindows1252
+	}
+	return d
+}
+
+// detectEncoding returns the encoding of the source that starts with the head.
+// Only the Auto encoding is detected from the byte order mark of the head, the
+// other ones are returned as they are.
+func detectEncoding(head []byte, e Encoding) Encoding {
+	if e != Auto {
+		return e
+	}
+	switch {
+	case bytes.HasPrefix(head, []byte(bomUTF16LE)):
+		return UTF16LE
+	case bytes.HasPrefix(head, []byte(bomUTF16BE)):
+		return UTF16BE
+	}
+	return UTF8
+}
+
+// isUTF16 reports whether the source that starts with the head is decoded from
+// UTF-16 with the encoding.
+func isUTF16(head []byte, e Encoding) bool {
+	e = detectEncoding(head, e)
+	return e == UTF16LE || e == UTF16BE
+}
+
+func bom(e Encoding) int {
+	if e == UTF8 {
+		return len(bomUTF8)
+	}
+	return len(bomUTF16LE)
+}
+
+// decoder decodes the bytes of r with the decode function.
+type decoder struct {
+	r io.Reader
+	// decode appends the UTF-8 form of the src to dst and returns the number of
+	// bytes it has used. If eof is false, it can leave an incomplete character
+	// at the end of src for the next call.
+	decode func(dst *bytes.Buffer, src []byte, eof bool) int
+	buf    []byte
+	in     []byte // bytes that are not decoded yet.
+	out    bytes.Buffer
+	err    error
+}
+
+func (d *decoder) Read(p []byte) (int, error) {
+	if d.buf == nil {
+		d.buf = make([]byte, chunkSize)
+	}
+	for d.out.Len() == 0 && d.err == nil {
+		n, err := d.r.Read(d.buf)
+		d.in = append(d.in, d.buf[:n]...)
+		d.err = err
+		used := d.decode(&d.out, d.in, err != nil)
+		d.in = d.in[used:]
+	}
+	if d.out.Len() > 0 {
+		return d.out.Read(p)
+	}
+	return 0, d.err
+}
+
+func decodeUTF16(order binary.ByteOrder) func(*bytes.Buffer, []byte, bool) int {
+	return func(dst *bytes.Buffer, src []byte, eof bool) int {
+		i := 0
+		for ; i+1 < len(src); i += 2 {
+			r := rune(order.Uint16(src[i:]))
+			if utf16.IsSurrogate(r) {
+				if i+3 >= len(src) && !eof {
+					// the other half is not read yet.
+					break
+				}
+				if i+3 < len(src) {
+					if r2 := utf16.DecodeRune(r, rune(order.Uint16(src[i+2:]))); r2 != utf8.RuneError {
+						dst.WriteRune(r2)
+						i += 2
+						continue
+					}
+				}
+				r = utf8.RuneError
+			}
+			dst.WriteRune(r)
+		}
+		if eof && i < len(src) {
+			// there is an odd byte at the end.
+			dst.WriteRune(utf8.RuneError)
+			i = len(src)
+		}
+		return i
+	}
+}
+
+func decodeLatin1(dst *bytes.Buffer, src []byte, _ bool) int {
+	for _, c := range src {
+		dst.WriteRune(rune(c))
+	}
+	return len(src)
+}
+
+func decodeWindows1252(dst *bytes.Buffer, src []byte, _ bool) int {
+	for _, c := range src {
+		if c >= 0x80 && c < 0xa0 {
+			dst.WriteRune(windows1252[c-0x80])
+			continue
+		}
+		dst.WriteRune(rune(c))
+	}
+	return len(src)
+}
+
+// NewEncoder returns a writer that encodes the UTF-8 input to the encoding and
+// writes it to w. The runes that can't be encoded are written as question
+// marks. Auto and UTF8 return w as it is.
+func NewEncoder(w io.Writer, e Encoding) io.Writer {
+	var encode func(dst []byte, r rune) []byte
+	switch e {
+	case Auto, UTF8:
+		return w
+	case UTF16LE:
+		encode = encodeUTF16(binary.LittleEndian)
+	case UTF16BE:
+		encode = encodeUTF16(binary.BigEndian)
+	case Latin1:
+		encode = encodeLatin1
+	case Windows1252:
+		encode = encodeWindows1252
+	}
+	return &encoder{w: w, encode: encode}
+}
+
+// encoder encodes the runes it receives with the encode function. Incomplete
+// runes at the end of a Write are kept for the next one.
+type encoder struct {
+	w      io.Writer
+	encode func(dst []byte, r rune) []byte
+	rest   []byte
+}
+
+func (e *encoder) Write(p []byte) (int, error) {
+	in := append(e.rest, p...)
+	out := make([]byte, 0, len(in))
+	i := 0
+	for i < len(in) {
+		if !utf8.FullRune(in[i:]) {
+			break
+		}
+		r, size := utf8.DecodeRune(in[i:])
+		out = e.encode(out, r)
+		i += size
+	}
+	e.rest = append([]byte(nil), in[i:]...)
+	if _, err := e.w.Write(out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func encodeUTF16(order binary.ByteOrder) func([]byte, rune) []byte {
+	return func(dst []byte, r rune) []byte {
+		b := make([]byte, 2)
+		for _, c := range utf16.Encode([]rune{r}) {
+			order.PutUint16(b, c)
+			dst = append(dst, b...)
+		}
+		return dst
+	}
+}
+
+func encodeLatin1(dst []byte, r rune) []byte {
+	if r > 0xff {
+		return append(dst, '?')
+	}
+	return append(dst, byte(r))
+}
+
+func encodeWindows1252(dst []byte, r rune) []byte {
+	for i, c := range windows1252 {
+		if c == r {
+			return append(dst, byte(0x80+i))
+		}
+	}
+	if r >= 0x80 && r < 0xa0 || r > 0xff {
+		return append(dst, '?')
+	}
+	return append(dst, byte(r))
+}
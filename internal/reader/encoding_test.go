@@ -0,0 +1,145 @@
+package reader_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/alecthomas/assert"
+	"github.com/arsham/blush/internal/reader"
+)
+
+func TestParseEncoding(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name string
+		want reader.Encoding
+	}{
+		{"auto", reader.Auto},
+		{"utf-8", reader.UTF8},
+		{"UTF8", reader.UTF8},
+		{"utf-16le", reader.UTF16LE},
+		{"UTF-16BE", reader.UTF16BE},
+		{"latin-1", reader.Latin1},
+		{"ISO-8859-1", reader.Latin1},
+		{"windows-1252", reader.Windows1252},
+		{"cp1252", reader.Windows1252},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := reader.ParseEncoding(tc.name)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	_, err := reader.ParseEncoding("ebcdic")
+	assert.True(t, errors.Is(err, reader.ErrUnknownEncoding))
+}
+
+func TestNewDecoder(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name     string
+		encoding reader.Encoding
+		input    string
+		want     string
+	}{
+		{"auto utf-8", reader.Auto, "café\n", "café\n"},
+		{"auto utf-8 bom", reader.Auto, "\xef\xbb\xbfcafé\n", "café\n"},
+		{"auto utf-16le bom", reader.Auto, "\xff\xfec\x00a\x00f\x00\xe9\x00\n\x00", "café\n"},
+		{"auto utf-16be bom", reader.Auto, "\xfe\xff\x00c\x00a\x00f\x00\xe9\x00\n", "café\n"},
+		{"auto short", reader.Auto, "a", "a"},
+		{"auto empty", reader.Auto, "", ""},
+		{"utf-8", reader.UTF8, "\xef\xbb\xbfa\xff", "a\xff"},
+		{"utf-16le", reader.UTF16LE, "h\x00i\x00", "hi"},
+		{"utf-16le bom", reader.UTF16LE, "\xff\xfeh\x00i\x00", "hi"},
+		{"utf-16be", reader.UTF16BE, "\x00h\x00i", "hi"},
+		{"utf-16le surrogates", reader.UTF16LE, "\x3d\xd8\x00\xde!\x00", "😀!"},
+		{"utf-16le lone surrogate", reader.UTF16LE, "\x3d\xd8!\x00", "�!"},
+		{"utf-16le cut surrogate", reader.UTF16LE, "!\x00\x3d\xd8", "!�"},
+		{"utf-16le odd byte", reader.UTF16LE, "h\x00i", "h�"},
+		{"latin-1", reader.Latin1, "caf\xe9 \x80", "café \u0080"},
+		{"windows-1252", reader.Windows1252, "caf\xe9 \x80\x96\x81", "café €–\u0081"},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			r := reader.NewDecoder(bytes.NewBufferString(tc.input), tc.encoding)
+			got, err := io.ReadAll(r)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, string(got))
+
+			// the decoders should work even if they get one byte at a time.
+			r = reader.NewDecoder(iotest.OneByteReader(bytes.NewBufferString(tc.input)), tc.encoding)
+			got, err = io.ReadAll(r)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, string(got))
+		})
+	}
+}
+
+func TestNewDecoderError(t *testing.T) {
+	t.Parallel()
+	e := errors.New("read error")
+	r := io.MultiReader(bytes.NewBufferString("h\x00i\x00"), iotest.ErrReader(e))
+	got, err := io.ReadAll(reader.NewDecoder(r, reader.UTF16LE))
+	assert.True(t, errors.Is(err, e))
+	assert.Equal(t, "hi", string(got))
+}
+
+func TestNewEncoder(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name     string
+		encoding reader.Encoding
+		input    string
+		want     string
+	}{
+		{"auto", reader.Auto, "café", "café"},
+		{"utf-8", reader.UTF8, "café", "café"},
+		{"utf-16le", reader.UTF16LE, "café😀", "c\x00a\x00f\x00\xe9\x00\x3d\xd8\x00\xde"},
+		{"utf-16be", reader.UTF16BE, "hé", "\x00h\x00\xe9"},
+		{"latin-1", reader.Latin1, "café €", "caf\xe9 ?"},
+		{"windows-1252", reader.Windows1252, "café €–\u0081\u0085", "caf\xe9 \x80\x96\x81?"},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			w := reader.NewEncoder(buf, tc.encoding)
+			// the runes can be split between the writes.
+			for _, c := range []byte(tc.input) {
+				n, err := w.Write([]byte{c})
+				assert.NoError(t, err)
+				assert.Equal(t, 1, n)
+			}
+			assert.Equal(t, tc.want, buf.String())
+		})
+	}
+}
+
+func TestMultiReaderEncoding(t *testing.T) {
+	t.Parallel()
+	m, err := reader.NewMultiReader(
+		reader.WithReader("one", io.NopCloser(bytes.NewBufferString("caf\xe9\n"))),
+		reader.WithEncoding(reader.Latin1),
+	)
+	assert.NoError(t, err)
+	_, r, err := m.NextSource()
+	assert.NoError(t, err)
+	got, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "café\n", string(got))
+
+	input := []testCase{{"a.txt", "\xff\xfeh\x00i\x00"}}
+	dirs := setup(t, input)
+	m, err = reader.NewMultiReader(reader.WithPaths(dirs, false))
+	assert.NoError(t, err)
+	got, err = io.ReadAll(m)
+	assert.NoError(t, err)
+	assert.Equal(t, "hi", string(got))
+}
@@ -0,0 +1,281 @@
+package reader
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultFollowInterval is the interval of checking the followed files for
+// new contents.
+const DefaultFollowInterval = 500 * time.Millisecond
+
+// WithFollow keeps the regular files of WithPaths open after they are read,
+// like "tail -F". When all the sources are read, NextSource waits for new
+// lines to be appended to any of the files and returns them as a new source
+// with the same name as the file. A line is returned when its newline is
+// written, therefore a line that is written in parts is not split. The files
+// are checked in turns every interval. If a file is replaced, for example when
+// it is rotated, the rest of the old file is read and then the new one is
+// opened. If a file is truncated, it is read again from the beginning. The
+// compressed files and the archives are not followed. When the ctx is done,
+// the incomplete last lines of the files are returned, and then NextSource
+// returns io.EOF. The Read method does not follow the files.
+func WithFollow(ctx context.Context, interval time.Duration) Conf {
+	return func(m *MultiReader) error {
+		if interval <= 0 {
+			interval = DefaultFollowInterval
+		}
+		m.follow = &follow{ctx: ctx, interval: interval}
+		return nil
+	}
+}
+
+// Following reports whether the MultiReader returns the contents that are
+// appended to the files as new sources with the same name.
+func (m *MultiReader) Following() bool {
+	return m.follow != nil
+}
+
+// follow keeps track of the files that are followed.
+type follow struct {
+	ctx      context.Context
+	interval time.Duration
+	files    []*tailed
+	last     int // index of the file that had new contents last time.
+}
+
+// open adds the file to the followed files and returns its complete lines. If
+// n is not zero, only the last n lines are returned. The encoding of the file
+// is found from the head, which is the beginning of the file, and is used for
+// the rest of its contents.
+func (fl *follow) open(t *tailed, head []byte, e Encoding, n uint) (io.ReadCloser, error) {
+	t.encoding = detectEncoding(head, e)
+	seek := n > 0 && !isUTF16(head, e)
+	if seek {
+		offset, err := tailOffset(t.f, t.info.Size(), n)
+		if err == nil {
+			_, err = t.f.Seek(offset, io.SeekStart)
+		}
+		if err != nil {
+			return nil, err
+		}
+		t.offset = offset
+	}
+	end, err := t.lineEnd(t.info.Size())
+	if err != nil {
+		return nil, err
+	}
+	t.end = end
+	fl.files = append(fl.files, t)
+	r := Decode(t, t.encoding)
+	if seek {
+		return seeked{r, t.offset}, nil
+	}
+	return Tail(r, n), nil
+}
+
+// next waits for new lines in any of the files and returns them decoded to
+// UTF-8. The files are checked in turns, starting from the one after the last
+// file that had new lines, therefore a busy file can't starve the others. The
+// e is the encoding of the MultiReader, which is used for finding the encoding
+// of the files that are read from the start again.
+func (fl *follow) next(e Encoding) (string, io.ReadCloser, error) {
+	if len(fl.files) == 0 {
+		return "", nil, io.EOF
+	}
+	for {
+		for i := range fl.files {
+			idx := (fl.last + 1 + i) % len(fl.files)
+			t := fl.files[idx]
+			ok, err := t.poll(e)
+			if err != nil {
+				fl.last = idx
+				return "", nil, errors.Wrap(err, t.name)
+			}
+			if ok {
+				fl.last = idx
+				return t.name, Decode(t, t.encoding), nil
+			}
+		}
+		select {
+		case <-fl.ctx.Done():
+			return fl.rest()
+		case <-time.After(fl.interval):
+		}
+	}
+}
+
+// rest returns the incomplete last line of a file, or io.EOF if there is none
+// left.
+func (fl *follow) rest() (string, io.ReadCloser, error) {
+	for _, t := range fl.files {
+		size, err := t.size()
+		if err != nil || size <= t.offset {
+			continue
+		}
+		t.end = size
+		return t.name, Decode(t, t.encoding), nil
+	}
+	return "", nil, io.EOF
+}
+
+// Close closes all the followed files.
+func (fl *follow) Close() error {
+	var err error
+	for _, t := range fl.files {
+		if e := t.f.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	fl.files = nil
+	return err
+}
+
+// tailed is a followed file. It is read up to the end of its current contents,
+// and its Close method doesn't close the file.
+type tailed struct {
+	name     string
+	f        *os.File
+	info     os.FileInfo
+	encoding Encoding // encoding of the file, found when it is read from the start.
+	offset   int64    // number of the bytes that are read.
+	end      int64    // offset of the end of the current contents.
+}
+
+func (t *tailed) Read(b []byte) (int, error) {
+	if t.offset >= t.end {
+		return 0, io.EOF
+	}
+	if int64(len(b)) > t.end-t.offset {
+		b = b[:t.end-t.offset]
+	}
+	n, err := t.f.Read(b)
+	t.offset += int64(n)
+	return n, err
+}
+
+// Close skips the rest of the current contents if they are not read.
+func (t *tailed) Close() error {
+	if t.offset < t.end {
+		if _, err := t.f.Seek(t.end, io.SeekStart); err != nil {
+			return err
+		}
+		t.offset = t.end
+	}
+	return nil
+}
+
+// poll reports whether there are new complete lines in the file since the
+// last time, and makes them the current contents. The file is opened again if
+// it is replaced, after the rest of the old file is read, including its
+// incomplete last line. It is read from the start if it is truncated.
+func (t *tailed) poll(e Encoding) (bool, error) {
+	size, err := t.size()
+	if err != nil {
+		return false, err
+	}
+	info, err := os.Stat(t.name)
+	replaced := err == nil && !os.SameFile(info, t.info)
+	if replaced && size <= t.offset {
+		n, err := os.Open(t.name) // nolint:gosec // we need this.
+		if err != nil {
+			// it is probably being replaced, it will be tried again.
+			return false, nil // nolint:nilerr // explained above.
+		}
+		info, err := n.Stat()
+		if err != nil {
+			n.Close() // nolint:errcheck,gosec // the other error is more important.
+			return false, err
+		}
+		t.f.Close() // nolint:errcheck,gosec // the file is already read.
+		t.f, t.info, t.offset = n, info, 0
+		size, replaced = info.Size(), false
+	}
+	if size < t.offset {
+		if _, err := t.f.Seek(0, io.SeekStart); err != nil {
+			return false, err
+		}
+		t.offset = 0
+	}
+	if size == t.offset {
+		return false, nil
+	}
+	if t.offset == 0 {
+		if err := t.detect(e); err != nil {
+			return false, err
+		}
+	}
+	end := size
+	if !replaced {
+		if end, err = t.lineEnd(size); err != nil {
+			return false, err
+		}
+	}
+	t.end = end
+	return end > t.offset, nil
+}
+
+// detect finds the encoding of the file from its beginning.
+func (t *tailed) detect(e Encoding) error {
+	head := make([]byte, len(bomUTF8))
+	n, err := t.f.ReadAt(head, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	t.encoding = detectEncoding(head[:n], e)
+	return nil
+}
+
+// lineEnd returns the offset of the end of the last complete line between the
+// offset and the size, or the offset if there isn't any.
+func (t *tailed) lineEnd(size int64) (int64, error) {
+	nl := newline(t.encoding)
+	width := int64(len(nl))
+	buf := make([]byte, chunkSize)
+	for end := size; end-t.offset >= width; {
+		start := end - chunkSize
+		if start < t.offset {
+			start = t.offset
+		}
+		chunk := buf[:end-start]
+		if _, err := t.f.ReadAt(chunk, start); err != nil {
+			return 0, err
+		}
+		for i := int64(len(chunk)) - width; i >= 0; i-- {
+			// the characters of UTF-16 start at even offsets.
+			if (start+i)%width == 0 && bytes.Equal(chunk[i:i+width], nl) {
+				return start + i + width, nil
+			}
+		}
+		if start == t.offset {
+			break
+		}
+		// a newline of UTF-16 can be split between two chunks.
+		end = start + width - 1
+	}
+	return t.offset, nil
+}
+
+func (t *tailed) size() (int64, error) {
+	info, err := t.f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// newline returns the newline character in the encoding.
+func newline(e Encoding) []byte {
+	switch e {
+	case UTF16LE:
+		return []byte("\n\x00")
+	case UTF16BE:
+		return []byte("\x00\n")
+	}
+	return []byte{'\n'}
+}
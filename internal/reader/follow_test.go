@@ -0,0 +1,182 @@
+package reader_test
+
+import (
+	"context"
+	"io"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert"
+	"github.com/arsham/blush/internal/reader"
+)
+
+func appendFile(t *testing.T, name, content string) {
+	t.Helper()
+	f, err := os.OpenFile(name, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o600)
+	assert.NoError(t, err)
+	_, err = f.WriteString(content)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+}
+
+func followed(t *testing.T, paths ...string) (*reader.MultiReader, context.CancelFunc) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	m, err := reader.NewMultiReader(
+		reader.WithPaths(paths, false),
+		reader.WithFollow(ctx, time.Millisecond),
+	)
+	assert.NoError(t, err)
+	assert.True(t, m.Following())
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, m.Close())
+	})
+	return m, cancel
+}
+
+func assertSource(t *testing.T, m *reader.MultiReader, name, content string) {
+	t.Helper()
+	got, r, err := m.NextSource()
+	assert.NoError(t, err)
+	assert.Equal(t, name, got)
+	b, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, content, string(b))
+}
+
+func TestWithFollowAppended(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	a, b := path.Join(dir, "a.log"), path.Join(dir, "b.log")
+	appendFile(t, a, "a1\n")
+	appendFile(t, b, "b1\n")
+	m, _ := followed(t, a, b)
+	assertSource(t, m, a, "a1\n")
+	assertSource(t, m, b, "b1\n")
+
+	appendFile(t, b, "b2\n")
+	assertSource(t, m, b, "b2\n")
+	appendFile(t, a, "a2\na3\n")
+	appendFile(t, b, "b3\n")
+	assertSource(t, m, a, "a2\na3\n")
+	assertSource(t, m, b, "b3\n")
+}
+
+func TestWithFollowTruncated(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	name := path.Join(dir, "a.log")
+	appendFile(t, name, "first line\n")
+	m, _ := followed(t, name)
+	assertSource(t, m, name, "first line\n")
+
+	err := os.WriteFile(name, []byte("new\n"), 0o600)
+	assert.NoError(t, err)
+	assertSource(t, m, name, "new\n")
+}
+
+func TestWithFollowRotated(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	name := path.Join(dir, "a.log")
+	appendFile(t, name, "one\n")
+	m, _ := followed(t, name)
+	assertSource(t, m, name, "one\n")
+
+	err := os.Rename(name, name+".1")
+	assert.NoError(t, err)
+	appendFile(t, name+".1", "two\n")
+	appendFile(t, name, "three\n")
+	assertSource(t, m, name, "two\n")
+	assertSource(t, m, name, "three\n")
+	appendFile(t, name, "four\n")
+	assertSource(t, m, name, "four\n")
+}
+
+func TestWithFollowStopped(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	name := path.Join(dir, "a.log")
+	appendFile(t, name, "one\n")
+	m, cancel := followed(t, name)
+	assertSource(t, m, name, "one\n")
+
+	cancel()
+	_, _, err := m.NextSource()
+	assert.Equal(t, io.EOF, err)
+	assert.Empty(t, m.FileName())
+}
+
+func TestWithFollowCompressed(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	name := path.Join(dir, "a.log.gz")
+	err := os.WriteFile(name, compressed(t, "gzip"), 0o600)
+	assert.NoError(t, err)
+	m, _ := followed(t, name)
+	assertSource(t, m, name, compressContent)
+
+	// there is nothing to follow.
+	_, _, err = m.NextSource()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestWithoutFollow(t *testing.T) {
+	t.Parallel()
+	m, err := reader.NewMultiReader(reader.WithReader("r", io.NopCloser(nil)))
+	assert.NoError(t, err)
+	assert.False(t, m.Following())
+}
+
+func TestWithFollowPartialLines(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	name := path.Join(dir, "a.log")
+	appendFile(t, name, "one\ntw")
+	m, cancel := followed(t, name)
+	assertSource(t, m, name, "one\n")
+
+	appendFile(t, name, "o\nERR")
+	assertSource(t, m, name, "two\n")
+	appendFile(t, name, "OR line\nlast")
+	assertSource(t, m, name, "ERROR line\n")
+
+	// the incomplete last line is returned when the following is stopped.
+	cancel()
+	assertSource(t, m, name, "last")
+	_, _, err := m.NextSource()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestWithFollowRotatedPartialLine(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	name := path.Join(dir, "a.log")
+	appendFile(t, name, "one\n")
+	m, _ := followed(t, name)
+	assertSource(t, m, name, "one\n")
+
+	appendFile(t, name, "two")
+	err := os.Rename(name, name+".1")
+	assert.NoError(t, err)
+	appendFile(t, name, "three\n")
+	assertSource(t, m, name, "two")
+	assertSource(t, m, name, "three\n")
+}
+
+func TestWithFollowUTF16(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	name := path.Join(dir, "a.log")
+	appendFile(t, name, "\xff\xfea\x00\n\x00b\x00")
+	m, _ := followed(t, name)
+	assertSource(t, m, name, "a\n")
+
+	// the newline of UTF-16 is split between two writes.
+	appendFile(t, name, "\n")
+	appendFile(t, name, "\x00\n\n\n\x00")
+	assertSource(t, m, name, "b\nਊ\n")
+}
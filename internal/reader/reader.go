@@ -2,7 +2,6 @@ package reader
 
 import (
 	"io"
-	"os"
 
 	"github.com/arsham/blush/internal/tools"
 	"github.com/pkg/errors"
@@ -17,6 +16,11 @@ var ErrNoReader = errors.New("no input")
 type MultiReader struct {
 	currentName string
 	readers     []*container
+	current     *container
+	archive     *archive
+	follow      *follow
+	encoding    Encoding
+	tail        uint
 }
 
 // NewMultiReader creates an instance of the MultiReader and passes it to all
@@ -51,7 +55,7 @@ func WithReader(name string, r io.ReadCloser) Conf {
 		c := &container{
 			get: func() (io.ReadCloser, error) {
 				m.currentName = name
-				return r, nil
+				return Tail(Decode(r, m.encoding), m.tail), nil
 			},
 		}
 		m.readers = append(m.readers, c)
@@ -62,8 +66,19 @@ func WithReader(name string, r io.ReadCloser) Conf {
 // WithPaths searches through the path and adds any files it finds to the
 // MultiReader. Each path will become its reader's name in the process. It
 // returns an error if any of given files are not found. It ignores any files
-// that cannot be read or opened.
+// that cannot be read or opened. The files that are compressed with gzip,
+// bzip2 or zlib are decompressed when they are read. Each file in a zip or a
+// tar archive becomes a separate source, named after the archive and its path
+// in it, like "bundle.zip!/logs/app.log". The tar archives can be compressed.
+// The ignored and the hidden files in the directories are skipped, see
+// tools.Walker for the details.
 func WithPaths(paths []string, recursive bool) Conf {
+	return WithWalker(paths, tools.Walker{Recursive: recursive})
+}
+
+// WithWalker is like WithPaths, but the files are found with the w, therefore
+// the ignored and the hidden files can be included.
+func WithWalker(paths []string, w tools.Walker) Conf {
 	return func(m *MultiReader) error {
 		if paths == nil {
 			return errors.Wrap(ErrNoReader, "WithPaths: nil paths")
@@ -71,7 +86,7 @@ func WithPaths(paths []string, recursive bool) Conf {
 		if len(paths) == 0 {
 			return errors.Wrap(ErrNoReader, "WithPaths: empty paths")
 		}
-		files, err := tools.Files(recursive, paths...)
+		files, err := w.Files(paths...)
 		if err != nil {
 			return errors.Wrap(err, "WithPaths")
 		}
@@ -80,8 +95,7 @@ func WithPaths(paths []string, recursive bool) Conf {
 			c := &container{
 				get: func() (io.ReadCloser, error) {
 					m.currentName = name
-					f, err := os.Open(name) // nolint:gosec // we need this.
-					return f, err
+					return m.openFile(name)
 				},
 			}
 			m.readers = append(m.readers, c)
@@ -122,8 +136,116 @@ func (m *MultiReader) Read(b []byte) (n int, err error) {
 	return 0, io.EOF
 }
 
-// Close does nothing.
-func (m *MultiReader) Close() error { return nil }
+// NextSource closes the previous source and returns the name and the reader of
+// the next one, therefore the contents of each source can be read separately.
+// The members of the archives are returned one by one. It returns io.EOF
+// when there are no more sources. You should not mix the Read and NextSource
+// calls.
+func (m *MultiReader) NextSource() (string, io.Reader, error) {
+	if err := m.closeCurrent(); err != nil {
+		return "", nil, errors.Wrap(err, "MultiReader.NextSource")
+	}
+	for {
+		if m.archive != nil {
+			name, r, err := m.archive.nextMember()
+			if errors.Is(err, io.EOF) {
+				err = m.archive.Close()
+				m.archive = nil
+				if err != nil {
+					return "", nil, errors.Wrap(err, "MultiReader.NextSource")
+				}
+				continue
+			}
+			if err != nil {
+				return "", nil, errors.Wrap(err, "MultiReader.NextSource")
+			}
+			m.currentName = name
+			m.current = &container{r: r, open: true}
+			return name, r, nil
+		}
+		if len(m.readers) == 0 && m.follow != nil {
+			name, r, err := m.follow.next(m.encoding)
+			if errors.Is(err, io.EOF) {
+				m.currentName = ""
+				return "", nil, io.EOF
+			}
+			if err != nil {
+				return "", nil, errors.Wrap(err, "MultiReader.NextSource")
+			}
+			m.currentName = name
+			m.current = &container{r: r, open: true}
+			return name, r, nil
+		}
+		if len(m.readers) == 0 {
+			m.currentName = ""
+			return "", nil, io.EOF
+		}
+		c := m.readers[0]
+		m.readers = m.readers[1:]
+		r, err := c.get()
+		if err != nil {
+			return "", nil, errors.Wrap(err, "MultiReader.NextSource")
+		}
+		if a, ok := r.(*archive); ok {
+			m.archive = a
+			continue
+		}
+		c.r, c.open = r, true
+		m.current = c
+		return m.currentName, r, nil
+	}
+}
+
+// CloseSource closes the current source that is returned by NextSource. It is
+// useful when the rest of the source is not needed, otherwise the source is
+// closed with the next call to NextSource. It does nothing if there is no
+// current source.
+func (m *MultiReader) CloseSource() error {
+	if err := m.closeCurrent(); err != nil {
+		return errors.Wrap(err, "MultiReader.CloseSource")
+	}
+	return nil
+}
+
+func (m *MultiReader) closeCurrent() error {
+	if m.current == nil {
+		return nil
+	}
+	err := m.current.r.Close()
+	m.current = nil
+	return err
+}
+
+// Close closes the current source and the readers that are open, and drops the
+// rest of them. Nothing is read after it is closed.
+func (m *MultiReader) Close() error {
+	err := m.closeCurrent()
+	if m.archive != nil {
+		if e := m.archive.Close(); e != nil && err == nil {
+			err = e
+		}
+		m.archive = nil
+	}
+	if m.follow != nil {
+		if e := m.follow.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	for _, c := range m.readers {
+		if !c.open {
+			continue
+		}
+		if e := c.r.Close(); e != nil && err == nil {
+			err = e
+		}
+	}
+	m.readers = nil
+	m.currentName = ""
+	if err != nil {
+		return errors.Wrap(err, "MultiReader.Close")
+	}
+	return nil
+}
 
 // FileName returns the current reader's name.
 func (m *MultiReader) FileName() string {
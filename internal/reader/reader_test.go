@@ -2,7 +2,9 @@ package reader_test
 
 import (
 	"bytes"
+	"errors"
 	"io"
+	"os"
 	"path"
 	"testing"
 
@@ -327,3 +329,158 @@ func TestNewMultiReaderNonRecursive(t *testing.T) {
 	}
 	assert.NotContains(t, buf.String(), c3)
 }
+
+func TestMultiReaderNextSource(t *testing.T) {
+	t.Parallel()
+	var called []string
+	closer := func(name, input string) nopCloser {
+		return nopCloser{
+			Reader: bytes.NewBufferString(input),
+			closeFunc: func() error {
+				called = append(called, name)
+				return nil
+			},
+		}
+	}
+	m, err := reader.NewMultiReader(
+		reader.WithReader("r1", closer("r1", "input one")),
+		reader.WithReader("", closer("r2", "input two")),
+	)
+	assert.NoError(t, err)
+
+	name, r, err := m.NextSource()
+	assert.NoError(t, err)
+	assert.Equal(t, "r1", name)
+	assert.Equal(t, "r1", m.FileName())
+	b, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "input one", string(b))
+	assert.Empty(t, called)
+
+	name, r, err = m.NextSource()
+	assert.NoError(t, err)
+	assert.Equal(t, "", name)
+	assert.Equal(t, []string{"r1"}, called)
+	b, err = io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "input two", string(b))
+
+	_, r, err = m.NextSource()
+	assert.Equal(t, io.EOF, err)
+	assert.Nil(t, r)
+	assert.Equal(t, []string{"r1", "r2"}, called)
+	assert.Equal(t, "", m.FileName())
+
+	_, _, err = m.NextSource()
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestMultiReaderNextSourceErrors(t *testing.T) {
+	t.Parallel()
+	e := errors.New("close error")
+	r := nopCloser{
+		Reader:    &bytes.Buffer{},
+		closeFunc: func() error { return e },
+	}
+	m, err := reader.NewMultiReader(reader.WithReader("r", r))
+	assert.NoError(t, err)
+	_, _, err = m.NextSource()
+	assert.NoError(t, err)
+	_, _, err = m.NextSource()
+	assert.True(t, errors.Is(err, e))
+
+	dir := t.TempDir()
+	name := path.Join(dir, "file.txt")
+	err = os.WriteFile(name, []byte("content"), 0o600)
+	assert.NoError(t, err)
+	m, err = reader.NewMultiReader(reader.WithPaths([]string{name}, false))
+	assert.NoError(t, err)
+	err = os.Remove(name)
+	assert.NoError(t, err)
+	_, _, err = m.NextSource()
+	assert.Error(t, err)
+}
+
+func TestMultiReaderClose(t *testing.T) {
+	t.Parallel()
+	var called []string
+	closer := func(name string, err error) nopCloser {
+		return nopCloser{
+			Reader: bytes.NewBufferString(name),
+			closeFunc: func() error {
+				called = append(called, name)
+				return err
+			},
+		}
+	}
+	m, err := reader.NewMultiReader(
+		reader.WithReader("r1", closer("r1", nil)),
+		reader.WithReader("r2", closer("r2", nil)),
+		reader.WithReader("r3", closer("r3", nil)),
+	)
+	assert.NoError(t, err)
+	_, _, err = m.NextSource()
+	assert.NoError(t, err)
+	_, _, err = m.NextSource()
+	assert.NoError(t, err)
+
+	err = m.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"r1", "r2"}, called)
+	assert.Equal(t, "", m.FileName())
+	_, _, err = m.NextSource()
+	assert.Equal(t, io.EOF, err)
+	b, err := io.ReadAll(m)
+	assert.NoError(t, err)
+	assert.Empty(t, b)
+
+	e := errors.New("close error")
+	m, err = reader.NewMultiReader(reader.WithReader("r", closer("r", e)))
+	assert.NoError(t, err)
+	_, _, err = m.NextSource()
+	assert.NoError(t, err)
+	err = m.Close()
+	assert.True(t, errors.Is(err, e))
+}
+
+func TestMultiReaderCloseSource(t *testing.T) {
+	t.Parallel()
+	var called []string
+	closer := func(name string, err error) nopCloser {
+		return nopCloser{
+			Reader: bytes.NewBufferString(name),
+			closeFunc: func() error {
+				called = append(called, name)
+				return err
+			},
+		}
+	}
+	e := errors.New("close error")
+	m, err := reader.NewMultiReader(
+		reader.WithReader("r1", closer("r1", nil)),
+		reader.WithReader("r2", closer("r2", e)),
+	)
+	assert.NoError(t, err)
+	err = m.CloseSource()
+	assert.NoError(t, err)
+	assert.Empty(t, called)
+
+	_, _, err = m.NextSource()
+	assert.NoError(t, err)
+	err = m.CloseSource()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"r1"}, called)
+	err = m.CloseSource()
+	assert.NoError(t, err)
+
+	name, _, err := m.NextSource()
+	assert.NoError(t, err)
+	assert.Equal(t, "r2", name)
+	assert.Equal(t, []string{"r1"}, called)
+	err = m.CloseSource()
+	assert.True(t, errors.Is(err, e))
+	assert.Equal(t, []string{"r1", "r2"}, called)
+
+	_, _, err = m.NextSource()
+	assert.Equal(t, io.EOF, err)
+}
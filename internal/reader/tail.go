@@ -0,0 +1,147 @@
+package reader
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// WithTail only reads the last n lines of each source. The start of the lines
+// of the plain files is found by reading them backwards from their end,
+// therefore the rest of them are not read. The other sources, like the
+// compressed files, are read to the end and only their last n lines are kept.
+// The sources have an Offset method that returns the byte offset of the start
+// of their last lines. The numbers of the lines before them are not known. It
+// has no effect if n is zero.
+func WithTail(n uint) Conf {
+	return func(m *MultiReader) error {
+		m.tail = n
+		return nil
+	}
+}
+
+// Tail returns a ReadCloser that only returns the last n lines of r. It reads
+// r to the end when it is read for the first time, keeping only the last n
+// lines. Its Offset method returns the number of the bytes before the last n
+// lines after it is read. Closing it closes r. If n is zero, r is returned.
+func Tail(r io.ReadCloser, n uint) io.ReadCloser {
+	if n == 0 {
+		return r
+	}
+	return &tail{r: r, n: n}
+}
+
+// tail keeps the last n lines of r.
+type tail struct {
+	r      io.ReadCloser
+	n      uint
+	out    *strings.Reader
+	offset int64 // number of the bytes before the last lines.
+	read   bool
+	err    error
+}
+
+func (t *tail) Read(b []byte) (int, error) {
+	if !t.read {
+		t.read = true
+		t.out, t.offset, t.err = lastLines(t.r, t.n)
+	}
+	n, err := t.out.Read(b)
+	if errors.Is(err, io.EOF) && t.err != nil {
+		return n, t.err
+	}
+	return n, err
+}
+
+func (t *tail) Close() error { return t.r.Close() }
+
+// Offset returns the byte offset of the start of the last lines in r.
+func (t *tail) Offset() int64 { return t.offset }
+
+// seeked is a source that is read from the offset of its input.
+type seeked struct {
+	io.ReadCloser
+	offset int64
+}
+
+// Offset returns the byte offset of the start of the source in its input.
+func (s seeked) Offset() int64 { return s.offset }
+
+// lastLines reads r to the end and returns its last n lines and the number of
+// the bytes before them. The lines are kept until there are twice as many as
+// n, therefore the memory it uses doesn't depend on n if r is shorter. The
+// lines that are read before an error are returned with the error.
+func lastLines(r io.Reader, n uint) (*strings.Reader, int64, error) {
+	var (
+		br    = bufio.NewReader(r)
+		lines []string
+		total int64
+		err   error
+	)
+	for {
+		var line string
+		line, err = br.ReadString('\n')
+		total += int64(len(line))
+		if line != "" {
+			lines = append(lines, line)
+			if n < uint(len(lines))/2 {
+				lines = append(lines[:0], lines[uint(len(lines))-n:]...)
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+	if errors.Is(err, io.EOF) {
+		err = nil
+	}
+	if n < uint(len(lines)) {
+		lines = lines[uint(len(lines))-n:]
+	}
+	var sb strings.Builder
+	for _, line := range lines {
+		sb.WriteString(line)
+	}
+	return strings.NewReader(sb.String()), total - int64(sb.Len()), err
+}
+
+// tailOffset returns the offset of the start of the last n lines of the file
+// with the size. The file is read backwards from its end in chunks until the
+// n-th newline is found, and it returns 0 if the file has less lines. The
+// newline at the end of the file does not start a new line.
+func tailOffset(f *os.File, size int64, n uint) (int64, error) {
+	end := size
+	if end > 0 {
+		last := make([]byte, 1)
+		if _, err := f.ReadAt(last, end-1); err != nil {
+			return 0, err
+		}
+		if last[0] == '\n' {
+			end--
+		}
+	}
+	buf := make([]byte, chunkSize)
+	var count uint
+	for end > 0 {
+		start := end - chunkSize
+		if start < 0 {
+			start = 0
+		}
+		chunk := buf[:end-start]
+		if _, err := f.ReadAt(chunk, start); err != nil {
+			return 0, err
+		}
+		for i := bytes.LastIndexByte(chunk, '\n'); i >= 0; i = bytes.LastIndexByte(chunk[:i], '\n') {
+			count++
+			if count == n {
+				return start + int64(i) + 1, nil
+			}
+		}
+		end = start
+	}
+	return 0, nil
+}
@@ -0,0 +1,147 @@
+package reader_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/alecthomas/assert"
+	"github.com/arsham/blush/internal/reader"
+)
+
+func TestWithTail(t *testing.T) {
+	t.Parallel()
+	var long strings.Builder
+	for i := 1; i <= 5000; i++ {
+		fmt.Fprintf(&long, "line %d\n", i)
+	}
+	tcs := []struct {
+		name    string
+		content string
+		n       uint
+		want    string
+	}{
+		{"empty", "", 2, ""},
+		{"newline", "\n", 2, "\n"},
+		{"last lines", "a\nb\nc\n", 2, "b\nc\n"},
+		{"no newline at end", "a\nb\nc", 2, "b\nc"},
+		{"empty lines", "a\n\n\n", 2, "\n\n"},
+		{"less lines", "a\nb\n", 5, "a\nb\n"},
+		{"same lines", "a\nb\n", 2, "a\nb\n"},
+		{"zero", "a\nb\n", 0, "a\nb\n"},
+		{"crlf", "a\r\nb\r\nc\r\n", 1, "c\r\n"},
+		{"bzip2 header", "BZh a\nb\nc\n", 1, "c\n"},
+		{"several chunks", long.String(), 3, "line 4998\nline 4999\nline 5000\n"},
+		{"all chunks", long.String(), 5000, long.String()},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			name := path.Join(t.TempDir(), "file.log")
+			err := os.WriteFile(name, []byte(tc.content), 0o600)
+			assert.NoError(t, err)
+			m, err := reader.NewMultiReader(
+				reader.WithPaths([]string{name}, false),
+				reader.WithTail(tc.n),
+			)
+			assert.NoError(t, err)
+			assertSource(t, m, name, tc.want)
+			assert.NoError(t, m.Close())
+		})
+	}
+}
+
+func TestWithTailStreams(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	gz := path.Join(dir, "app.log.gz")
+	err := os.WriteFile(gz, compressed(t, "gzip"), 0o600)
+	assert.NoError(t, err)
+	zipped := path.Join(dir, "bundle.zip")
+	err = os.WriteFile(zipped, zipFile(t, archiveMembers), 0o600)
+	assert.NoError(t, err)
+	utf16 := path.Join(dir, "utf16.log")
+	err = os.WriteFile(utf16, []byte("\xff\xfea\x00\n\x00b\x00\n\x00"), 0o600)
+	assert.NoError(t, err)
+
+	m, err := reader.NewMultiReader(
+		reader.WithPaths([]string{gz, zipped, utf16}, false),
+		reader.WithReader("stdin", io.NopCloser(bytes.NewBufferString("1\n2\n3"))),
+		reader.WithTail(1),
+	)
+	assert.NoError(t, err)
+	assertSource(t, m, gz, "bbb\n")
+	assertSource(t, m, zipped+"!/logs/app.log", "ERROR two\n")
+	assertSource(t, m, zipped+"!/logs/db.log", "three\n")
+	assertSource(t, m, utf16, "b\n")
+	assertSource(t, m, "stdin", "3")
+	assert.NoError(t, m.Close())
+}
+
+func TestWithTailFollow(t *testing.T) {
+	t.Parallel()
+	name := path.Join(t.TempDir(), "a.log")
+	appendFile(t, name, "one\ntwo\nthree\n")
+	m, _ := followed(t, name)
+	assert.NoError(t, reader.WithTail(1)(m))
+	assertSource(t, m, name, "three\n")
+
+	appendFile(t, name, "four\n")
+	assertSource(t, m, name, "four\n")
+}
+
+func TestTail(t *testing.T) {
+	t.Parallel()
+	r := io.NopCloser(bytes.NewBufferString("a\nb\nc\n"))
+	assert.Equal(t, r, reader.Tail(r, 0))
+
+	b, err := io.ReadAll(reader.Tail(r, 2))
+	assert.NoError(t, err)
+	assert.Equal(t, "b\nc\n", string(b))
+
+	// the memory doesn't depend on n.
+	r = io.NopCloser(bytes.NewBufferString("a\nb\n"))
+	b, err = io.ReadAll(reader.Tail(r, 100000000000))
+	assert.NoError(t, err)
+	assert.Equal(t, "a\nb\n", string(b))
+
+	errBoom := errors.New("boom")
+	bad := io.MultiReader(bytes.NewBufferString("a\nb"), iotest.ErrReader(errBoom))
+	b, err = io.ReadAll(reader.Tail(io.NopCloser(bad), 1))
+	assert.True(t, errors.Is(err, errBoom))
+	assert.Equal(t, "b", string(b))
+}
+
+func TestWithTailOffset(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	plain := path.Join(dir, "app.log")
+	err := os.WriteFile(plain, []byte("aaa\nbbb\nccc\n"), 0o600)
+	assert.NoError(t, err)
+	gz := path.Join(dir, "app.log.gz")
+	err = os.WriteFile(gz, compressed(t, "gzip"), 0o600)
+	assert.NoError(t, err)
+
+	m, err := reader.NewMultiReader(
+		reader.WithPaths([]string{plain, gz}, false),
+		reader.WithTail(1),
+	)
+	assert.NoError(t, err)
+	for _, want := range []int64{8, int64(len(compressContent) - len("bbb\n"))} {
+		_, r, err := m.NextSource()
+		assert.NoError(t, err)
+		_, err = io.ReadAll(r)
+		assert.NoError(t, err)
+		o, ok := r.(interface{ Offset() int64 })
+		assert.True(t, ok)
+		assert.Equal(t, want, o.Offset())
+	}
+	assert.NoError(t, m.Close())
+}
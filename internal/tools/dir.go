@@ -3,25 +3,61 @@ package tools
 
 import (
 	"errors"
-	"io"
 	"os"
 	"path"
 	"path/filepath"
+	"strings"
 )
 
+// Walker finds the files in the paths. The files and the directories that
+// match the patterns of the IgnoreFiles, the hidden ones, and the .git
+// directories are skipped when they are found in the directories, unless
+// NoIgnore or Hidden is set. The .git directories are only returned if both
+// are set. The paths that are given are never skipped. If a path is in a git
+// repository, the IgnoreFiles of its parent directories up to the root of the
+// repository are read as well.
+//
+// The globs of Include, Exclude and ExcludeDir match the names of the files
+// and the directories, or their paths relative to the searched path if they
+// have a slash. A "*" matches anything but a slash, and "**" matches any
+// number of directories. If Include or Types are set, only the files that
+// match them are returned.
+type Walker struct {
+	Recursive bool // search the directories under the paths.
+	NoIgnore  bool // don't read the IgnoreFiles.
+	Hidden    bool // include the files and directories that start with a dot.
+	// Include are the globs of the files that are returned.
+	Include []string
+	// Exclude are the globs of the files that are not returned.
+	Exclude []string
+	// ExcludeDir are the globs of the directories that are not searched.
+	ExcludeDir []string
+	// Types are the names of the FileTypes or TypeGlobs of the files that are
+	// returned.
+	Types []string
+	// TypeGlobs are the globs of the file types in addition to FileTypes. They
+	// take precedence over FileTypes with the same names.
+	TypeGlobs map[string][]string
+}
+
 // Files returns all files found in paths. If recursive is false, it only
-// returns the immediate files in the paths.
+// returns the immediate files in the paths. Binary files are returned as well,
+// use IsPlainText on their contents to tell them apart. The ignored and the
+// hidden files are skipped as described in Walker.
 func Files(recursive bool, paths ...string) ([]string, error) {
-	var (
-		fileList []string
-		fn       = files
-	)
-	if recursive {
-		fn = rfiles
-	}
+	return Walker{Recursive: recursive}.Files(paths...)
+}
 
+// Files returns all files found in paths. If Recursive is false, it only
+// returns the immediate files in the paths.
+func (w Walker) Files(paths ...string) ([]string, error) {
+	filter, err := w.filter()
+	if err != nil {
+		return nil, err
+	}
+	var fileList []string
 	for _, p := range paths {
-		f, err := fn(p)
+		f, err := w.files(p, filter)
 		if err != nil {
 			return nil, err
 		}
@@ -30,9 +66,7 @@ func Files(recursive bool, paths ...string) ([]string, error) {
 	if len(fileList) == 0 {
 		return nil, errors.New("no files found")
 	}
-	fileList = unique(fileList)
-	fileList = nonBinary(fileList)
-	return fileList, nil
+	return unique(fileList), nil
 }
 
 func unique(fileList []string) []string {
@@ -50,66 +84,81 @@ func unique(fileList []string) []string {
 	return ret
 }
 
-func nonBinary(fileList []string) []string {
-	ret := make([]string, 0, len(fileList))
-	for _, f := range fileList {
-		if isPlainText(f) {
-			ret = append(ret, f)
-		}
+func (w Walker) files(location string, f *filter) ([]string, error) {
+	s, err := os.Stat(location)
+	if os.IsPermission(err) {
+		return nil, nil
 	}
-	return ret
-}
-
-func rfiles(location string) ([]string, error) {
-	fileList := []string{}
-	err := filepath.Walk(location, func(location string, f os.FileInfo, err error) error {
-		if os.IsPermission(err) {
-			return nil
-		}
-		if err != nil {
-			return err
-		}
-		if !f.IsDir() {
-			fileList = append(fileList, location)
-		}
-		return nil
-	})
 	if err != nil {
 		return nil, err
 	}
-	return fileList, nil
+	if !s.IsDir() {
+		return []string{location}, nil
+	}
+	var lists []*ignoreList
+	if !w.NoIgnore {
+		lists = parentIgnoreFiles(location)
+	}
+	wk := &walk{Walker: w, root: location, filter: f, found: []string{}}
+	err = wk.walk(location, lists)
+	return wk.found, err
 }
 
-func files(location string) ([]string, error) {
-	if s, err := os.Stat(location); err == nil && !s.IsDir() {
-		return []string{location}, nil
+// walk holds the state of walking through a path.
+type walk struct {
+	Walker
+	root   string
+	filter *filter
+	found  []string
+}
+
+// walk adds the files of the dir to the found files, and the files of its sub
+// directories if Recursive is set. The lists are the rules of the ignore files
+// of the parent directories.
+func (w *walk) walk(dir string, lists []*ignoreList) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsPermission(err) {
+		return nil
 	}
-	files, err := os.ReadDir(location)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	if !w.NoIgnore {
+		if l := readIgnoreFiles(dir); l != nil {
+			lists = append(lists[:len(lists):len(lists)], l)
+		}
 	}
-	fileList := []string{}
-	for _, f := range files {
-		if !f.IsDir() {
-			p := path.Join(location, f.Name())
-			fileList = append(fileList, p)
+	for _, e := range entries {
+		name := e.Name()
+		p := path.Join(dir, name)
+		if w.skip(name, e.IsDir()) || (!w.NoIgnore && ignored(lists, p, e.IsDir())) {
+			continue
+		}
+		rel, err := filepath.Rel(w.root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !e.IsDir() {
+			if w.filter.file(rel) {
+				w.found = append(w.found, p)
+			}
+			continue
+		}
+		if !w.Recursive || !w.filter.dir(rel) {
+			continue
+		}
+		if err := w.walk(p, lists); err != nil {
+			return err
 		}
 	}
-	return fileList, nil
+	return nil
 }
 
-// TODO: we should ignore the line in search stage instead.
-func isPlainText(name string) bool {
-	f, err := os.Open(name) // nolint:gosec // this is required.
-	if err != nil {
-		return false
+// skip reports whether the entry is hidden or is a .git directory.
+func (w Walker) skip(name string, isDir bool) bool {
+	if !w.Hidden && strings.HasPrefix(name, ".") {
+		return true
 	}
-	defer f.Close() // nolint:errcheck,gosec // not required.
-	header := make([]byte, 512)
-	_, err = f.Read(header)
-	if err != nil && !errors.Is(err, io.EOF) {
-		return false
-	}
-
-	return IsPlainText(string(header))
+	return !w.NoIgnore && isDir && name == ".git"
 }
@@ -10,3 +10,8 @@ func ExampleFiles() {
 	dirs := []string{"~/Documents", "/tmp"}
 	tools.Files(false, dirs...)
 }
+
+func ExampleWalker_Files() {
+	w := tools.Walker{Recursive: true, Hidden: true}
+	w.Files("~/Documents", "/tmp")
+}
@@ -7,7 +7,6 @@ import (
 	"os"
 	"path"
 	"sort"
-	"strings"
 	"testing"
 
 	"github.com/alecthomas/assert"
@@ -203,43 +202,14 @@ func setupBinaryFile(t *testing.T) (str, name string) {
 	return txt.Name(), binary.Name()
 }
 
-func TestIgnoreNonTextFiles(t *testing.T) {
+func TestFilesIncludeBinaryFiles(t *testing.T) {
 	t.Parallel()
 	txt, binary := setupBinaryFile(t)
 	paths := path.Dir(txt)
 	got, err := tools.Files(false, paths)
 	assert.NoError(t, err)
 	assert.True(t, inSlice(txt, got))
-	assert.False(t, inSlice(binary, got))
-}
-
-func TestUnPrintableButTextContents(t *testing.T) {
-	t.Parallel()
-	tcs := []struct {
-		name    string
-		input   string
-		wantLen int
-	}{
-		{"null", string(rune(0)), 1},
-		{"space", " ", 1},
-		{"return", "\r", 1},
-		{"line feed", "\n", 1},
-		{"tab", "\t", 1},
-		{"mix", "\na\tbbb\n\n\n\t\t\n \t \n \r\nsjdk", 1},
-		{"one", string(rune(1)), 0},
-		{"bell", "\b", 0},
-		{"bell in middle", "a\bc", 0},
-	}
-	for _, tc := range tcs {
-		t.Run(tc.name, func(t *testing.T) {
-			file, err := ioutil.TempFile(t.TempDir(), "blush_text")
-			assert.NoError(t, err)
-			file.WriteString(tc.input)
-			got, err := tools.Files(false, file.Name())
-			assert.NoError(t, err)
-			assert.Len(t, got, tc.wantLen, strings.Join(got, "\n"))
-		})
-	}
+	assert.True(t, inSlice(binary, got))
 }
 
 func TestFilesIgnoreDirs(t *testing.T) {
@@ -0,0 +1,132 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// ErrUnknownType is returned if a file type is not defined.
+var ErrUnknownType = errors.New("unknown file type")
+
+// FileTypes are the globs of the file names of each file type.
+var FileTypes = map[string][]string{
+	"c":        {"*.c", "*.h"},
+	"cpp":      {"*.cpp", "*.cc", "*.cxx", "*.hpp", "*.hh", "*.hxx", "*.h"},
+	"csharp":   {"*.cs"},
+	"css":      {"*.css", "*.scss", "*.sass", "*.less"},
+	"csv":      {"*.csv", "*.tsv"},
+	"docker":   {"Dockerfile", "Dockerfile.*", "*.dockerfile"},
+	"go":       {"*.go"},
+	"html":     {"*.html", "*.htm", "*.xhtml"},
+	"ini":      {"*.ini", "*.cfg", "*.conf"},
+	"java":     {"*.java"},
+	"js":       {"*.js", "*.mjs", "*.cjs", "*.jsx"},
+	"json":     {"*.json", "*.jsonl"},
+	"kotlin":   {"*.kt", "*.kts"},
+	"log":      {"*.log", "*.log.[0-9]*"},
+	"lua":      {"*.lua"},
+	"make":     {"Makefile", "makefile", "GNUmakefile", "*.mk"},
+	"markdown": {"*.md", "*.markdown"},
+	"md":       {"*.md", "*.markdown"},
+	"php":      {"*.php"},
+	"proto":    {"*.proto"},
+	"py":       {"*.py", "*.pyi"},
+	"ruby":     {"*.rb", "Gemfile", "Rakefile"},
+	"rust":     {"*.rs"},
+	"sh":       {"*.sh", "*.bash", "*.zsh", ".bashrc", ".zshrc", ".profile"},
+	"sql":      {"*.sql"},
+	"swift":    {"*.swift"},
+	"toml":     {"*.toml"},
+	"ts":       {"*.ts", "*.tsx", "*.mts", "*.cts"},
+	"txt":      {"*.txt"},
+	"xml":      {"*.xml", "*.xsd", "*.xsl"},
+	"yaml":     {"*.yaml", "*.yml"},
+}
+
+// glob is a compiled glob. If it has a slash, it matches the path of a file
+// relative to the path that is searched, otherwise it matches its name.
+type glob struct {
+	re   *regexp.Regexp
+	path bool
+}
+
+func compileGlobs(globs []string) ([]glob, error) {
+	ret := make([]glob, 0, len(globs))
+	for _, g := range globs {
+		p := strings.Contains(strings.TrimSuffix(g, "/"), "/")
+		re, err := regexp.Compile("^" + globToRegexp(strings.Trim(g, "/")) + "$")
+		if err != nil {
+			return nil, fmt.Errorf("glob %q: %w", g, err)
+		}
+		ret = append(ret, glob{re: re, path: p})
+	}
+	return ret, nil
+}
+
+func matchGlobs(globs []glob, rel string) bool {
+	name := path.Base(rel)
+	for _, g := range globs {
+		if g.path && g.re.MatchString(rel) || !g.path && g.re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// filter decides which files and directories are returned by a Walker.
+type filter struct {
+	include    []glob
+	exclude    []glob
+	excludeDir []glob
+	types      []glob
+}
+
+func (w Walker) filter() (*filter, error) {
+	var (
+		f     = &filter{}
+		types []string
+		err   error
+	)
+	for _, t := range w.Types {
+		globs, ok := w.TypeGlobs[t]
+		if !ok {
+			globs, ok = FileTypes[t]
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownType, t)
+		}
+		types = append(types, globs...)
+	}
+	if f.types, err = compileGlobs(types); err != nil {
+		return nil, err
+	}
+	if f.include, err = compileGlobs(w.Include); err != nil {
+		return nil, err
+	}
+	if f.exclude, err = compileGlobs(w.Exclude); err != nil {
+		return nil, err
+	}
+	if f.excludeDir, err = compileGlobs(w.ExcludeDir); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// file reports whether the file with the relative path should be returned.
+func (f *filter) file(rel string) bool {
+	if len(f.include) > 0 && !matchGlobs(f.include, rel) {
+		return false
+	}
+	if len(f.types) > 0 && !matchGlobs(f.types, rel) {
+		return false
+	}
+	return !matchGlobs(f.exclude, rel)
+}
+
+// dir reports whether the directory with the relative path should be searched.
+func (f *filter) dir(rel string) bool {
+	return !matchGlobs(f.excludeDir, rel)
+}
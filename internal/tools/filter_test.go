@@ -0,0 +1,85 @@
+package tools_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/alecthomas/assert"
+	"github.com/arsham/blush/internal/tools"
+)
+
+func TestWalkerFilters(t *testing.T) {
+	t.Parallel()
+	dir := setupTree(t, map[string]string{
+		"main.go":              "",
+		"main_test.go":         "",
+		"app.log":              "",
+		"app.log.1":            "",
+		"config.yaml":          "",
+		"deploy/values.yml":    "",
+		"web/app.js":           "",
+		"web/app.min.js":       "",
+		"web/lib/util.min.js":  "",
+		"vendor/lib/lib.go":    "",
+		"src/vendor/x.go":      "",
+		"src/cmd/tool/main.go": "",
+		"Makefile":             "",
+	})
+	tcs := []struct {
+		name   string
+		walker tools.Walker
+		want   []string
+	}{
+		{"include", tools.Walker{Include: []string{"*.log"}}, []string{"app.log"}},
+		{"include several", tools.Walker{Include: []string{"*.log", "*.log.*"}}, []string{"app.log", "app.log.1"}},
+		{"exclude", tools.Walker{Exclude: []string{"*.min.js"}, Include: []string{"*.js"}}, []string{"web/app.js"}},
+		{"exclude path", tools.Walker{Exclude: []string{"web/*.min.js"}, Include: []string{"*.js"}}, []string{"web/app.js", "web/lib/util.min.js"}},
+		{"exclude stars", tools.Walker{Exclude: []string{"**/lib/*.js"}, Include: []string{"*.js"}}, []string{"web/app.js", "web/app.min.js"}},
+		{"exclude dir", tools.Walker{ExcludeDir: []string{"vendor"}, Include: []string{"*.go"}}, []string{"main.go", "main_test.go", "src/cmd/tool/main.go"}},
+		{"exclude dir path", tools.Walker{ExcludeDir: []string{"/vendor/"}, Include: []string{"*.go"}}, []string{"main.go", "main_test.go", "src/vendor/x.go", "src/cmd/tool/main.go"}},
+		{"include path", tools.Walker{Include: []string{"src/**/main.go"}}, []string{"src/cmd/tool/main.go"}},
+		{"type", tools.Walker{Types: []string{"yaml"}}, []string{"config.yaml", "deploy/values.yml"}},
+		{"types", tools.Walker{Types: []string{"log", "make"}}, []string{"app.log", "app.log.1", "Makefile"}},
+		{"type and exclude", tools.Walker{Types: []string{"go"}, Exclude: []string{"*_test.go"}, ExcludeDir: []string{"vendor"}}, []string{"main.go", "src/cmd/tool/main.go"}},
+		{"type and include", tools.Walker{Types: []string{"go"}, Include: []string{"main*"}}, []string{"main.go", "main_test.go", "src/cmd/tool/main.go"}},
+		{"user type", tools.Walker{Types: []string{"web"}, TypeGlobs: map[string][]string{"web": {"*.js", "*.html"}}}, []string{"web/app.js", "web/app.min.js", "web/lib/util.min.js"}},
+		{"user type replaces", tools.Walker{Types: []string{"go"}, TypeGlobs: map[string][]string{"go": {"*_test.go"}}}, []string{"main_test.go"}},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tc.walker.Recursive = true
+			got, err := tc.walker.Files(dir)
+			assert.NoError(t, err)
+			stringSliceEq(t, join(dir, tc.want...), got)
+		})
+	}
+
+	t.Run("given files", func(t *testing.T) {
+		t.Parallel()
+		want := join(dir, "config.yaml")
+		got, err := tools.Walker{Types: []string{"go"}}.Files(want...)
+		assert.NoError(t, err)
+		stringSliceEq(t, want, got)
+	})
+
+	t.Run("not recursive", func(t *testing.T) {
+		t.Parallel()
+		got, err := tools.Walker{Types: []string{"go"}}.Files(dir)
+		assert.NoError(t, err)
+		stringSliceEq(t, join(dir, "main.go", "main_test.go"), got)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		t.Parallel()
+		_, err := tools.Walker{Types: []string{"cobol"}}.Files(dir)
+		assert.True(t, errors.Is(err, tools.ErrUnknownType))
+	})
+
+	t.Run("nothing matches", func(t *testing.T) {
+		t.Parallel()
+		_, err := tools.Walker{Include: []string{"*.cobol"}}.Files(dir)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,194 @@
+package tools
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// IgnoreFiles are the names of the files that hold the patterns of the files
+// and the directories that are not searched. The patterns of a file apply to
+// its directory and all the directories under it. The patterns of the files
+// that come later in the list, and the ones in deeper directories, take
+// precedence.
+var IgnoreFiles = []string{".gitignore", ".ignore", ".blushignore"}
+
+// ignoreRule is a pattern of an ignore file.
+type ignoreRule struct {
+	re      *regexp.Regexp
+	negate  bool // the pattern starts with "!", which includes the path again.
+	dirOnly bool // the pattern ends with "/", which only matches directories.
+}
+
+// ignoreList holds the rules of the ignore files of a directory. The rules of
+// the parent directories of a searched path are matched with the paths that are
+// relative to the searched path, in the dir, joined to the prefix, which is the
+// path of the searched path in the parent directory.
+type ignoreList struct {
+	dir    string
+	prefix string
+	rules  []ignoreRule
+}
+
+// readIgnoreFiles returns the rules of the ignore files in the dir, or nil if
+// there aren't any. The files that can't be read are skipped.
+func readIgnoreFiles(dir string) *ignoreList {
+	l := &ignoreList{dir: dir}
+	for _, name := range IgnoreFiles {
+		f, err := os.Open(filepath.Join(dir, name)) // nolint:gosec // we need this.
+		if err != nil {
+			continue
+		}
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			if r, ok := parseIgnoreRule(sc.Text()); ok {
+				l.rules = append(l.rules, r)
+			}
+		}
+		f.Close() // nolint:errcheck,gosec // it is only read.
+	}
+	if len(l.rules) == 0 {
+		return nil
+	}
+	return l
+}
+
+// parentIgnoreFiles returns the rules of the ignore files of the parent
+// directories of the root, up to the root of its repository, which is the
+// directory that has a .git in it. It returns nil if the root is not in a
+// repository, or if it is the root of the repository.
+func parentIgnoreFiles(root string) []*ignoreList {
+	dir, err := filepath.Abs(root)
+	if err != nil || isRepo(dir) {
+		return nil
+	}
+	var (
+		lists  []*ignoreList
+		prefix string
+	)
+	for {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			// the root is not in a repository.
+			return nil
+		}
+		prefix = filepath.Join(filepath.Base(dir), prefix)
+		dir = parent
+		if l := readIgnoreFiles(dir); l != nil {
+			l.dir, l.prefix = root, prefix
+			// the rules of the deeper directories take precedence.
+			lists = append([]*ignoreList{l}, lists...)
+		}
+		if isRepo(dir) {
+			return lists
+		}
+	}
+}
+
+func isRepo(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// parseIgnoreRule parses a line of an ignore file with the rules of the
+// gitignore files. It returns false for the empty lines and the comments.
+func parseIgnoreRule(line string) (ignoreRule, bool) {
+	var r ignoreRule
+	line = strings.TrimSuffix(line, "\r")
+	if strings.HasSuffix(line, "\\ ") {
+		line = strings.TrimRight(line[:len(line)-2], " ") + "\\ "
+	} else {
+		line = strings.TrimRight(line, " ")
+	}
+	if line == "" || line[0] == '#' {
+		return r, false
+	}
+	if line[0] == '!' {
+		r.negate = true
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		r.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return r, false
+	}
+	// a pattern with a slash at the beginning or the middle is relative to the
+	// directory of the ignore file, otherwise it matches at any level.
+	prefix := "(?:.*/)?"
+	if strings.Contains(line, "/") {
+		prefix = ""
+		line = strings.TrimPrefix(line, "/")
+	}
+	re, err := regexp.Compile("^" + prefix + globToRegexp(line) + "$")
+	if err != nil {
+		return r, false
+	}
+	r.re = re
+	return r, true
+}
+
+// globToRegexp converts the glob of an ignore file to a regular expression. A
+// "*" matches anything but a slash, and "**" matches any number of
+// directories.
+func globToRegexp(glob string) string {
+	var sb strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case strings.HasPrefix(glob[i:], "**/") && (i == 0 || glob[i-1] == '/'):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case glob[i:] == "**" && (i == 0 || glob[i-1] == '/'):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '[':
+			end := strings.IndexByte(glob[i+1:], ']')
+			if end < 0 {
+				sb.WriteString(`\[`)
+				continue
+			}
+			class := glob[i+1 : i+1+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			sb.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		case c == '\\' && i+1 < len(glob):
+			i++
+			sb.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return sb.String()
+}
+
+// ignored reports whether the path is ignored by the lists. The last pattern
+// that matches the path decides whether it is ignored.
+func ignored(lists []*ignoreList, p string, isDir bool) bool {
+	var ret bool
+	for _, l := range lists {
+		rel, err := filepath.Rel(l.dir, p)
+		if err != nil {
+			continue
+		}
+		rel = filepath.ToSlash(filepath.Join(l.prefix, rel))
+		for _, r := range l.rules {
+			if r.dirOnly && !isDir {
+				continue
+			}
+			if r.re.MatchString(rel) {
+				ret = !r.negate
+			}
+		}
+	}
+	return ret
+}
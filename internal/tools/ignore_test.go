@@ -0,0 +1,218 @@
+package tools_test
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/alecthomas/assert"
+	"github.com/arsham/blush/internal/tools"
+)
+
+// setupTree creates the files with their contents in a temporary directory and
+// returns the directory.
+func setupTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		p := path.Join(dir, name)
+		err := os.MkdirAll(path.Dir(p), 0o700)
+		assert.NoError(t, err)
+		err = os.WriteFile(p, []byte(content), 0o600)
+		assert.NoError(t, err)
+	}
+	return dir
+}
+
+func join(dir string, names ...string) []string {
+	ret := make([]string, len(names))
+	for i, name := range names {
+		ret[i] = path.Join(dir, name)
+	}
+	return ret
+}
+
+func TestWalkerIgnoreFiles(t *testing.T) {
+	t.Parallel()
+	dir := setupTree(t, map[string]string{
+		".gitignore":              "# build output\n*.log\n!keep.log\n/build/\nvendor\ndocs/**/draft.md\n",
+		"main.go":                 "",
+		"app.log":                 "",
+		"keep.log":                "",
+		"build/out.txt":           "",
+		"src/build/file.go":       "",
+		"src/vendor/lib.go":       "",
+		"vendor/lib.go":           "",
+		"docs/draft.md":           "",
+		"docs/a/b/draft.md":       "",
+		"docs/readme.md":          "",
+		"src/.ignore":             "*.tmp\n",
+		"src/a.tmp":               "",
+		"src/sub/b.tmp":           "",
+		"src/sub/.gitignore":      "!b.tmp\n",
+		"src/sub/c.go":            "",
+		"other/.blushignore":      "*.go\n",
+		"other/.gitignore":        "!x.go\n",
+		"other/x.go":              "",
+		"other/y.txt":             "",
+		".hidden/file.txt":        "",
+		".env":                    "",
+		".git/config":             "",
+		"node_modules/.gitignore": "*\n",
+		"node_modules/pkg/a.js":   "",
+	})
+	tcs := []struct {
+		name   string
+		walker tools.Walker
+		want   []string
+	}{
+		{"default", tools.Walker{Recursive: true}, join(dir,
+			"main.go", "keep.log", "src/build/file.go", "docs/readme.md",
+			"src/sub/b.tmp", "src/sub/c.go", "other/y.txt",
+		)},
+		{"no ignore", tools.Walker{Recursive: true, NoIgnore: true}, join(dir,
+			"main.go", "app.log", "keep.log", "build/out.txt", "src/build/file.go",
+			"src/vendor/lib.go", "vendor/lib.go", "docs/draft.md", "docs/a/b/draft.md",
+			"docs/readme.md", "src/a.tmp", "src/sub/b.tmp", "src/sub/c.go", "other/x.go",
+			"other/y.txt", "node_modules/pkg/a.js",
+		)},
+		{"hidden", tools.Walker{Recursive: true, Hidden: true}, join(dir,
+			".gitignore", "main.go", "keep.log", "src/build/file.go", "docs/readme.md",
+			"src/.ignore", "src/sub/b.tmp", "src/sub/.gitignore", "src/sub/c.go", "other/.blushignore", "other/.gitignore", "other/y.txt",
+			".hidden/file.txt", ".env",
+		)},
+		{"not recursive", tools.Walker{}, join(dir, "main.go", "keep.log")},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tc.walker.Files(dir)
+			assert.NoError(t, err)
+			stringSliceEq(t, tc.want, got)
+		})
+	}
+
+	t.Run("everything", func(t *testing.T) {
+		t.Parallel()
+		got, err := tools.Walker{Recursive: true, NoIgnore: true, Hidden: true}.Files(dir)
+		assert.NoError(t, err)
+		assert.True(t, inSlice(path.Join(dir, ".git/config"), got))
+		assert.True(t, inSlice(path.Join(dir, "app.log"), got))
+	})
+}
+
+func TestWalkerGivenPaths(t *testing.T) {
+	t.Parallel()
+	dir := setupTree(t, map[string]string{
+		".gitignore":       "*.log\n",
+		"app.log":          "",
+		".hidden/file.txt": "",
+	})
+	want := join(dir, "app.log", ".hidden/file.txt")
+	got, err := tools.Files(true, want[0], path.Join(dir, ".hidden"))
+	assert.NoError(t, err)
+	stringSliceEq(t, want, got)
+}
+
+func TestWalkerPatterns(t *testing.T) {
+	t.Parallel()
+	tcs := []struct {
+		name    string
+		pattern string
+		ignored []string
+		kept    []string
+	}{
+		{"name", "foo", []string{"foo", "a/foo", "b/foo/x"}, []string{"foobar", "a/xfoo"}},
+		{"anchored", "/foo", []string{"foo"}, []string{"a/foo"}},
+		{"anchored directory", "/foo", []string{"foo/x"}, []string{"a/foo/x"}},
+		{"middle slash", "a/foo", []string{"a/foo"}, []string{"b/a/foo"}},
+		{"star", "*.txt", []string{"x.txt", "a/b/y.txt"}, []string{"x.txt.go"}},
+		{"star in path", "a/*.txt", []string{"a/x.txt"}, []string{"a/b/x.txt"}},
+		{"question mark", "?.md", []string{"a.md"}, []string{"ab.md"}},
+		{"class", "[ab].go", []string{"a.go", "b.go"}, []string{"c.go"}},
+		{"negated class", "[!ab].go", []string{"c.go"}, []string{"a.go"}},
+		{"leading stars", "**/foo", []string{"foo", "a/b/foo"}, []string{"foox"}},
+		{"trailing stars", "a/**", []string{"a/x", "a/b/c"}, []string{"b/a/x"}},
+		{"middle stars", "a/**/z", []string{"a/z", "a/b/c/z"}, []string{"b/a/z"}},
+		{"directory", "foo/", []string{"foo/x", "a/foo/y"}, []string{"b/foo"}},
+		{"escaped hash", "\\#x", []string{"#x"}, []string{"x"}},
+		{"comment", "#x", nil, []string{"#x", "x"}},
+		{"trailing space", "x  ", []string{"x"}, nil},
+		{"escaped space", "x\\ ", []string{"x "}, []string{"x"}},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			files := map[string]string{".gitignore": tc.pattern + "\n", "keep": ""}
+			for _, name := range append(tc.ignored, tc.kept...) {
+				files[name] = ""
+			}
+			dir := setupTree(t, files)
+			got, err := tools.Files(true, dir)
+			assert.NoError(t, err)
+			stringSliceEq(t, join(dir, append(tc.kept, "keep")...), got)
+		})
+	}
+}
+
+func TestWalkerParentIgnoreFiles(t *testing.T) {
+	t.Parallel()
+	dir := setupTree(t, map[string]string{
+		".gitignore":            "*.txt\n",
+		"repo/.git/HEAD":        "",
+		"repo/.gitignore":       "*.min.js\n/src/gen/\n!keep.min.js\n",
+		"repo/src/.ignore":      "*.tmp\n",
+		"repo/src/app.js":       "",
+		"repo/src/app.min.js":   "",
+		"repo/src/keep.min.js":  "",
+		"repo/src/notes.txt":    "",
+		"repo/src/a.tmp":        "",
+		"repo/src/gen/x.js":     "",
+		"repo/src/lib/gen/y.js": "",
+		"repo/src/lib/b.min.js": "",
+		"plain/.gitignore":      "*.log\n",
+		"plain/sub/a.log":       "",
+		"plain/sub/.gitignore":  "*.tmp\n",
+		"plain/sub/b.tmp":       "",
+		"plain/sub/c.go":        "",
+	})
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	rel, err := filepath.Rel(wd, path.Join(dir, "repo/src"))
+	assert.NoError(t, err)
+
+	tcs := []struct {
+		name   string
+		walker tools.Walker
+		root   string
+		want   []string
+	}{
+		{"sub directory", tools.Walker{Recursive: true}, path.Join(dir, "repo/src"), join(dir,
+			"repo/src/app.js", "repo/src/keep.min.js", "repo/src/notes.txt", "repo/src/lib/gen/y.js",
+		)},
+		{"deeper", tools.Walker{Recursive: true}, path.Join(dir, "repo/src/lib"), join(dir,
+			"repo/src/lib/gen/y.js",
+		)},
+		{"relative", tools.Walker{Recursive: true}, rel, join(rel,
+			"app.js", "keep.min.js", "notes.txt", "lib/gen/y.js",
+		)},
+		{"no ignore", tools.Walker{Recursive: true, NoIgnore: true}, path.Join(dir, "repo/src/lib"), join(dir,
+			"repo/src/lib/gen/y.js", "repo/src/lib/b.min.js",
+		)},
+		{"not in repository", tools.Walker{Recursive: true}, path.Join(dir, "plain/sub"), join(dir,
+			"plain/sub/a.log", "plain/sub/c.go",
+		)},
+	}
+	for _, tc := range tcs {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tc.walker.Files(tc.root)
+			assert.NoError(t, err)
+			stringSliceEq(t, tc.want, got)
+		})
+	}
+}
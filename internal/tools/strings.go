@@ -1,20 +1,46 @@
 package tools
 
 import (
-	"unicode"
+	"strings"
+	"unicode/utf8"
 )
 
-// IsPlainText returns false if at least one of the runes in the input is not
-// represented as a plain text in a file. Null is an exception.
+// These are the byte order marks that IsPlainText recognises.
+const (
+	bomUTF8    = "\xef\xbb\xbf"
+	bomUTF16LE = "\xff\xfe"
+	bomUTF16BE = "\xfe\xff"
+)
+
+// IsPlainText returns false if the input has a NUL byte, or more than a tenth
+// of it is invalid UTF-8 or control characters other than white spaces and the
+// escape character. An incomplete rune at the end of the input is ignored, as
+// the input is usually the beginning of a file. If the input starts with a
+// UTF-16 byte order mark it is always plain text, and the UTF-8 byte order mark
+// is skipped.
 func IsPlainText(input string) bool {
-	for _, r := range input {
-		switch r {
-		case 0, '\n', '\t', '\r':
-			continue
-		}
-		if r > unicode.MaxASCII || !unicode.IsPrint(r) {
+	switch {
+	case strings.HasPrefix(input, bomUTF16LE), strings.HasPrefix(input, bomUTF16BE):
+		return true
+	case strings.HasPrefix(input, bomUTF8):
+		input = input[len(bomUTF8):]
+	}
+	var suspicious int
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		switch {
+		case r == 0:
 			return false
+		case r == utf8.RuneError && size == 1:
+			if !utf8.FullRuneInString(input[i:]) {
+				// the last rune is cut off.
+				return suspicious*10 <= i
+			}
+			suspicious++
+		case r < ' ' && !strings.ContainsRune("\t\n\v\f\r\x1b", r), r == 0x7f:
+			suspicious++
 		}
+		i += size
 	}
-	return true
+	return suspicious*10 <= len(input)
 }
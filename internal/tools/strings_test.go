@@ -23,8 +23,23 @@ func TestIsPlainText(t *testing.T) {
 		{"bell", "\b", false},
 		{"mix", "\n\n \r\nsjdk", true},
 		{"1", "\x01", false},
-		{"zero in middle", "n\x00b", true},
+		{"zero in middle", "n\x00b", false},
 		{"bell in middle", "a\bc", false},
+		{"nul", "\x00", false},
+		{"empty", "", true},
+		{"accents", "café crème brûlée", true},
+		{"cjk", "日本語のテキスト", true},
+		{"emoji", "done 🎉\n", true},
+		{"escape sequences", "\x1b[38;5;4mblue\x1b[0m", true},
+		{"few control characters", "a\bcdefghijklmnopqrst", true},
+		{"latin-1", "caf\xe9 cr\xe8me and some more text", true},
+		{"invalid utf-8", "\xfd\xfc\xfbabc", false},
+		{"cut rune at the end", "text 日本\xe8\xaa", true},
+		{"utf-8 bom", "\xef\xbb\xbftext", true},
+		{"utf-8 bom nul", "\xef\xbb\xbf\x00", false},
+		{"utf-16le bom", "\xff\xfet\x00e\x00", true},
+		{"utf-16be bom", "\xfe\xff\x00t\x00e", true},
+		{"png", "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR", false},
 	}
 	for _, tc := range tcs {
 		tc := tc
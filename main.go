@@ -1,7 +1,11 @@
 package main
 
-import "github.com/arsham/blush/cmd"
+import (
+	"os"
+
+	"github.com/arsham/blush/cmd"
+)
 
 func main() {
-	cmd.Main()
+	os.Exit(cmd.Main())
 }